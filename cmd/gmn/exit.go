@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/jangraefen/go-man/pkg/manager"
+	"github.com/jangraefen/go-man/pkg/tasks"
+)
+
+// The exit codes of gmn. Scripts depend on them, so they must never change their meaning.
+const (
+	// exitFailure is used for any failure, that has no exit code of its own.
+	exitFailure = 1
+	// exitUsage is used if the arguments or flags are invalid. It matches the exit code, that is used if the flags cannot be
+	// parsed at all.
+	exitUsage = 2
+	// exitNotInstalled is used if a version is required, that is not installed.
+	exitNotInstalled = 3
+	// exitAlreadyInstalled is used if a version is installed, that is already installed.
+	exitAlreadyInstalled = 4
+	// exitReleaseNotFound is used if no release matches the requested version.
+	exitReleaseNotFound = 5
+	// exitPlatformUnavailable is used if a release is not available for the requested platform, or an installation cannot be
+	// used on the current one.
+	exitPlatformUnavailable = 6
+	// exitChecksumMismatch is used if a file does not match its expected checksum.
+	exitChecksumMismatch = 7
+)
+
+var (
+	errUsage = errors.New("usage")
+
+	exitCodes = []struct {
+		kind error
+		code int
+	}{
+		{kind: errUsage, code: exitUsage},
+		{kind: manager.ErrNotInstalled, code: exitNotInstalled},
+		{kind: manager.ErrAlreadyInstalled, code: exitAlreadyInstalled},
+		{kind: manager.ErrReleaseNotFound, code: exitReleaseNotFound},
+		{kind: manager.ErrPlatformUnavailable, code: exitPlatformUnavailable},
+		{kind: manager.ErrChecksumMismatch, code: exitChecksumMismatch},
+	}
+)
+
+// exitCode is a function that determines the exit code, that belongs to the kind of an error.
+func exitCode(err error) int {
+	for _, exitCode := range exitCodes {
+		if errors.Is(err, exitCode.kind) {
+			return exitCode.code
+		}
+	}
+
+	return exitFailure
+}
+
+// commonExitCode is a function that determines the exit code, that belongs to all of the given errors. If their exit codes
+// differ, the generic failure exit code is used.
+func commonExitCode(errs []error) int {
+	code := exitFailure
+	for index, err := range errs {
+		if index > 0 && exitCode(err) != code {
+			return exitFailure
+		}
+
+		code = exitCode(err)
+	}
+
+	return code
+}
+
+// fatalOnError is a function that reports an error and exits with the exit code that belongs to it, if there is any.
+func fatalOnError(task *tasks.Task, err error) {
+	if err == nil {
+		return
+	}
+
+	task.Errorf("%s", err)
+	os.Exit(exitCode(err))
+}
+
+// fatalIff is a function that reports an error and exits with the generic failure exit code, if a condition matches.
+// It provides the same formatting as the fmt package does.
+func fatalIff(task *tasks.Task, condition bool, format string, args ...interface{}) {
+	if condition {
+		fatalOnError(task, fmt.Errorf(format, args...))
+	}
+}
+
+// usageIff is a function that reports an invalid usage and exits with the usage exit code, if a condition matches.
+// It provides the same formatting as the fmt package does.
+func usageIff(task *tasks.Task, condition bool, format string, args ...interface{}) {
+	if condition {
+		fatalOnError(task, manager.NewError(errUsage, format, args...))
+	}
+}
+
+// exitIff is a function that exits with the generic failure exit code, if a condition matches. Unlike fatalIff, nothing is
+// printed, since the printed document already describes the failure.
+func exitIff(condition bool) {
+	if condition {
+		os.Exit(exitFailure)
+	}
+}
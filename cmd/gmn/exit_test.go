@@ -0,0 +1,57 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jangraefen/go-man/pkg/manager"
+)
+
+func TestExitCode(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{name: "generic", err: errors.New("failure"), expected: exitFailure},
+		{name: "usage", err: manager.NewError(errUsage, "invalid"), expected: exitUsage},
+		{name: "not installed", err: manager.NewError(manager.ErrNotInstalled, "missing"), expected: exitNotInstalled},
+		{name: "already installed", err: manager.NewError(manager.ErrAlreadyInstalled, "twice"), expected: exitAlreadyInstalled},
+		{name: "release not found", err: manager.NewError(manager.ErrReleaseNotFound, "unknown"), expected: exitReleaseNotFound},
+		{
+			name:     "platform unavailable",
+			err:      manager.NewError(manager.ErrPlatformUnavailable, "unsupported"),
+			expected: exitPlatformUnavailable,
+		},
+		{
+			name:     "checksum mismatch",
+			err:      manager.NewError(manager.ErrChecksumMismatch, "deviates"),
+			expected: exitChecksumMismatch,
+		},
+		{
+			name:     "wrapped",
+			err:      fmt.Errorf("installing: %w", manager.NewError(manager.ErrChecksumMismatch, "deviates")),
+			expected: exitChecksumMismatch,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, exitCode(testCase.err))
+		})
+	}
+}
+
+func TestCommonExitCode(t *testing.T) {
+	notInstalled := manager.NewError(manager.ErrNotInstalled, "missing")
+	checksumMismatch := manager.NewError(manager.ErrChecksumMismatch, "deviates")
+
+	assert.Equal(t, exitFailure, commonExitCode(nil))
+	assert.Equal(t, exitNotInstalled, commonExitCode([]error{notInstalled}))
+	assert.Equal(t, exitNotInstalled, commonExitCode([]error{notInstalled, notInstalled}))
+	assert.Equal(t, exitFailure, commonExitCode([]error{notInstalled, checksumMismatch}))
+	assert.Equal(t, exitFailure, commonExitCode([]error{errors.New("failure"), notInstalled}))
+}
@@ -1,15 +1,20 @@
 package main
 
 import (
+	"errors"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strings"
 
 	"github.com/hashicorp/go-version"
 	"github.com/posener/cmd"
 	"github.com/posener/complete/v2/predict"
 
 	"github.com/jangraefen/go-man/internal/fileutil"
+	"github.com/jangraefen/go-man/internal/httputil"
+	"github.com/jangraefen/go-man/internal/shellutil"
 	"github.com/jangraefen/go-man/pkg/manager"
 	"github.com/jangraefen/go-man/pkg/releases"
 	"github.com/jangraefen/go-man/pkg/tasks"
@@ -20,13 +25,88 @@ var (
 		cmd.OptName("gmn"),
 		cmd.OptDetails("A manager for Go installations"),
 	)
+	rootOffline = root.Bool(
+		"offline",
+		false,
+		"If set, only the cached release list is used and nothing is retrieved from the network",
+	)
+	rootRefresh = root.Bool(
+		"refresh",
+		false,
+		"If set, the cached release list is refreshed regardless of its age",
+	)
+	rootCacheTTL = root.Duration(
+		"cache-ttl",
+		releases.DefaultCacheTTL,
+		"Duration for that a cached release list is used before it is refreshed",
+	)
+	rootTimeout = root.Duration(
+		"timeout",
+		httputil.Timeout,
+		"Duration that a download may stall without receiving any data, before it is aborted",
+	)
+	rootLockTimeout = root.Duration(
+		"lock-timeout",
+		manager.DefaultLockTimeout,
+		"Duration that is waited for another gmn process to finish",
+	)
+	rootRetries = root.Int(
+		"retries",
+		httputil.Retries,
+		"Number of times that a download is retried, if it failed with a transient error",
+	)
+	rootOutput = root.String(
+		"output",
+		outputText,
+		"Format in which results are printed",
+		predict.OptValues(outputText, outputJSON),
+		predict.OptCheck(),
+	)
+	rootLog = root.String(
+		"log",
+		logAuto,
+		"Format in which the progress is logged",
+		predict.OptValues(logAuto, logTree, logPlain, logVerbose, logQuiet, logJSON),
+		predict.OptCheck(),
+	)
 
-	list         = root.SubCommand("list", "Lists of all available Go releases")
+	list         = root.SubCommand("list", "Lists the available and installed Go releases")
 	listUnstable = list.Bool(
 		"unstable",
 		false,
 		"Unlocks the listing of unstable Go versions",
 	)
+	listInstalled = list.Bool(
+		"installed",
+		false,
+		"If set, only installed Go versions are listed",
+	)
+	listRemote = list.Bool(
+		"remote",
+		false,
+		"If set, only Go releases that are not installed yet are listed",
+	)
+	listOS = list.String(
+		"os",
+		runtime.GOOS,
+		"Operating system for that the listed Go releases have to be available",
+		predict.OptValues("freebsd", "darwin", "linux", "windows"),
+		predict.OptCheck(),
+	)
+	listArch = list.String(
+		"arch",
+		runtime.GOARCH,
+		"Processor architecture for that the listed Go releases have to be available",
+		predict.OptValues("386", "amd64", "armv61", "ppc64le", "s390x"),
+		predict.OptCheck(),
+	)
+	listKind = list.String(
+		"kind",
+		string(releases.ArchiveFile),
+		"Kind of file that the listed Go releases have to provide",
+		predict.OptValues(string(releases.ArchiveFile), string(releases.SourceFile), string(releases.InstallerFile)),
+		predict.OptCheck(),
+	)
 
 	install         = root.SubCommand("install", "Installs one or more new Go releases")
 	installUnstable = install.Bool(
@@ -48,9 +128,30 @@ var (
 		predict.OptValues("386", "amd64", "armv61", "ppc64le", "s390x"),
 		predict.OptCheck(),
 	)
+	installJobs = install.Int(
+		"jobs",
+		runtime.NumCPU(),
+		"Number of Go releases that are installed concurrently",
+	)
+	installFromArchive = install.String(
+		"from-archive",
+		"",
+		"Local archive file of a Go distribution that will be installed, instead of downloading a release",
+		predict.OptPredictor(predict.Files("*")),
+	)
+	installFromSource = install.Bool(
+		"from-source",
+		false,
+		"If set, Go is built from the source archive of a release with an installed Go as bootstrap",
+	)
+	installSha256 = install.String(
+		"sha256",
+		"",
+		"Checksum that the archive given by -from-archive has to match",
+	)
 	installVersions = install.Args(
 		"[versions...]",
-		"Versions of Go that will be installed. 'latest' or any version number",
+		"Versions of Go that will be installed. 'latest', 'tip', 'git:<ref>', any version number or a constraint like '1.15.x'",
 	)
 
 	uninstall    = root.SubCommand("uninstall", "Uninstall an existing Go installation")
@@ -59,6 +160,20 @@ var (
 		false,
 		"If set, all installations of Go will be uninstalled",
 	)
+	uninstallOS = uninstall.String(
+		"os",
+		runtime.GOOS,
+		"Operating system for that the Go installation was installed",
+		predict.OptValues("freebsd", "darwin", "linux", "windows"),
+		predict.OptCheck(),
+	)
+	uninstallArch = uninstall.String(
+		"arch",
+		runtime.GOARCH,
+		"Processor architecture for that the Go installation was installed",
+		predict.OptValues("386", "amd64", "armv61", "ppc64le", "s390x"),
+		predict.OptCheck(),
+	)
 	uninstallVersions = uninstall.Args(
 		"[versions...]",
 		"The versions that should be uninstalled",
@@ -67,123 +182,697 @@ var (
 	selectz        = root.SubCommand("select", "Selects the default Go installation")
 	selectVersions = selectz.Args(
 		"[version]",
-		"The version that should be selected",
+		"The version that should be selected. Any version number or a version constraint like '~1.15'",
 	)
 
 	unselect = root.SubCommand("unselect", "Unselects the default Go installation")
 
 	cleanup = root.SubCommand("cleanup", "Removes all Go installations, that are not considered stable")
+
+	doctor = root.SubCommand("doctor", "Diagnoses problems with the Go installations and the environment they are used in")
+
+	cache      = root.SubCommand("cache", "Manages the cache of downloaded Go releases")
+	cacheList  = cache.SubCommand("list", "Lists all cached archives")
+	cachePrune = cache.SubCommand("prune", "Removes all cached archives of Go versions that are not installed")
+	cacheClear = cache.SubCommand("clear", "Removes all cached archives and release lists")
+
+	current     = root.SubCommand("current", "Shows the Go installation that applies to the working directory")
+	currentPath = current.Bool(
+		"path",
+		false,
+		"If set, only the directory of the installation is printed",
+	)
+
+	envz     = root.SubCommand("env", "Prints the shell configuration that is needed to use the Go installations")
+	envShell = envz.String(
+		"shell",
+		"",
+		"Shell for that the configuration is printed (defaults to your current shell)",
+		predict.OptValues("bash", "zsh", "fish", "powershell"),
+		predict.OptCheck(),
+	)
+	envHook = envz.Bool(
+		"hook",
+		false,
+		"If set, a hook is added that switches to the Go installation that applies to the working directory",
+	)
+
+	execz       = root.SubCommand("exec", "Runs a command with a Go installation, without changing the selection")
+	execCommand = execz.Args(
+		"[version] -- [command...]",
+		"The version whose installation is used and the command that should be run",
+	)
+
+	shim = root.SubCommand("shim", "Installs shims for the go and gofmt tools, that apply to the working directory")
+
+	verify         = root.SubCommand("verify", "Verifies that Go installations match the manifest recorded at installation")
+	verifyVersions = verify.Args(
+		"[versions...]",
+		"The versions that should be verified. Defaults to all installed versions",
+	)
+
+	which      = root.SubCommand("which", "Shows the path of a Go tool that applies to the working directory")
+	whichTools = which.Args(
+		"[tool]",
+		"The tool that should be located. Defaults to 'go'",
+	)
 )
 
 func main() {
 	task := &tasks.Task{
-		ErrorExitCode: 1,
-		Output:        os.Stdout,
-		Error:         os.Stderr,
+		Output: os.Stdout,
+		Error:  os.Stderr,
 	}
 
 	if !fileutil.PathExists(gomanRoot()) {
-		task.FatalOnError(os.MkdirAll(gomanRoot(), 0755))
+		fatalOnError(task, os.MkdirAll(gomanRoot(), 0755))
+	}
+
+	// When called through a shim, the program name is the name of the shimmed tool and all arguments belong to that tool.
+	if tool, isShim := manager.ShimTool(os.Args[0]); isShim {
+		handleShimCall(task, tool, os.Args[1:])
+		return
 	}
 
 	// Parse the command line arguments. Any errors will get caught be the library and will cause the usage to be printed.
 	// The program will exit afterwards.
 	_ = root.Parse()
+	configureOutput(task)
+
+	httputil.Timeout = *rootTimeout
+	httputil.Retries = *rootRetries
+	releases.ChecksumBaseURL = checksumBaseURL()
+	releases.Source = &releases.CachedSource{
+		Source:    releaseSource(),
+		Directory: cacheDirectory(),
+		TTL:       *rootCacheTTL,
+		Offline:   *rootOffline,
+		Refresh:   *rootRefresh,
+	}
 
 	switch {
 	case list.Parsed():
-		handleList(task, *listUnstable)
+		handleList(task, manager.ListOptions{
+			InstalledOnly: *listInstalled,
+			RemoteOnly:    *listRemote,
+			ReleaseType:   releases.SelectReleaseType(*listUnstable),
+			OS:            *listOS,
+			Arch:          *listArch,
+			Kind:          releases.FileKind(*listKind),
+		})
+	case install.Parsed() && (*installFromArchive != "" || *installSha256 != ""):
+		handleInstallArchive(task, *installFromArchive, *installSha256, *installFromSource, *installVersions)
+	case install.Parsed() && *installFromSource:
+		handleInstallSource(task, *installUnstable, *installVersions)
 	case install.Parsed():
-		handleInstall(task, *installUnstable, *installOS, *installArch, *installVersions)
+		handleInstall(task, *installUnstable, *installOS, *installArch, *installJobs, *installVersions)
 	case uninstall.Parsed():
-		handleUninstall(task, *uninstallAll, *uninstallVersions)
+		handleUninstall(task, *uninstallAll, *uninstallOS, *uninstallArch, *uninstallVersions)
 	case selectz.Parsed():
 		handleSelect(task, *selectVersions)
 	case unselect.Parsed():
 		handleUnselect(task)
 	case cleanup.Parsed():
 		handleCleanup(task)
+	case doctor.Parsed():
+		handleDoctor(task)
+	case cacheList.Parsed():
+		handleCacheList(task)
+	case cachePrune.Parsed():
+		handleCachePrune(task)
+	case cacheClear.Parsed():
+		handleCacheClear(task)
+	case current.Parsed():
+		handleCurrent(task, *currentPath)
+	case envz.Parsed():
+		handleEnv(task, *envShell, *envHook)
+	case verify.Parsed():
+		handleVerify(task, *verifyVersions)
+	case which.Parsed():
+		handleWhich(task, *whichTools)
+	case shim.Parsed():
+		handleShim(task)
+	case execz.Parsed():
+		handleExec(task, *execCommand)
 	}
 }
 
-func handleList(task *tasks.Task, all bool) {
-	task.Printf("List of available releases:")
-	listTask := task.Step()
+func handleList(task *tasks.Task, options manager.ListOptions) {
+	usageIff(task, options.InstalledOnly && options.RemoteOnly, "Both installed and remote flag given, skipping.")
 
-	releaseList, err := releases.ListAll(releases.SelectReleaseType(all))
-	listTask.FatalOnError(err)
+	goManager := newManager(task)
 
-	for _, r := range releaseList {
-		listTask.Printf("%s", r.GetVersionName())
+	listed, err := goManager.List(options)
+	fatalOnError(task, err)
+
+	if jsonOutput() {
+		printDocument(task, listDocument{Versions: newVersionDocuments(listed)})
+		return
+	}
+
+	switch {
+	case options.InstalledOnly:
+		printResult(task, "List of installed versions for %s-%s:", options.OS, options.Arch)
+	case options.RemoteOnly:
+		printResult(task, "List of releases for %s-%s, that are not installed:", options.OS, options.Arch)
+	default:
+		printResult(task, "List of releases for %s-%s:", options.OS, options.Arch)
+	}
+
+	for _, line := range manager.GroupByReleaseLine(listed) {
+		printResult(task, "-> %s", line.Name)
+
+		for _, listedVersion := range line.Versions {
+			var markers []string
+			if listedVersion.Installed {
+				markers = append(markers, "installed")
+			}
+			if listedVersion.Selected {
+				markers = append(markers, "selected")
+			}
+
+			if len(markers) == 0 {
+				printResult(task, "   -> %s", listedVersion.Name)
+			} else {
+				printResult(task, "   -> %s (%s)", listedVersion.Name, strings.Join(markers, ", "))
+			}
+		}
 	}
 }
 
-func handleInstall(task *tasks.Task, unstable bool, operatingSystem, arch string, versionNames []string) {
-	task.FatalIff(len(versionNames) == 0, "No versions given to install, skipping")
+func handleInstall(task *tasks.Task, unstable bool, operatingSystem, arch string, jobs int, versionNames []string) {
+	usageIff(task, len(versionNames) == 0, "No versions given to install, skipping")
+	usageIff(task, jobs < 1, "At least one job is needed to install, skipping")
+
+	releaseType := releases.SelectReleaseType(unstable)
+	versionNumbers := make(version.Collection, 0, len(versionNames))
+	var refs []string
+	for _, versionName := range versionNames {
+		if ref, isRevision := manager.ParseRevision(versionName); isRevision {
+			refs = append(refs, ref)
+			continue
+		}
+
+		versionNumbers = append(versionNumbers, resolveReleaseVersion(task, versionName, releaseType))
+	}
 
-	if len(versionNames) == 1 && versionNames[0] == "latest" {
-		latest, err := releases.GetLatest(releases.SelectReleaseType(unstable))
-		task.FatalOnError(err)
+	goManager := newInstallManager(task)
+	installations := goManager.Installations()
+	var errs []error
+	if len(versionNumbers) > 0 {
+		errs = append(errs, goManager.InstallAll(versionNumbers, operatingSystem, arch, releaseType, jobs))
+	}
 
-		versionNames = []string{latest.GetVersionNumber().String()}
+	// Revisions are built after the releases are installed, so that these can already be used to bootstrap the builds.
+	for _, ref := range refs {
+		_, err := goManager.InstallRevision(gitRemote(), ref)
+		errs = append(errs, err)
 	}
 
+	printInstalled(task, goManager, installations, errs...)
+}
+
+func handleInstallArchive(task *tasks.Task, archiveFile, checksum string, fromSource bool, versionNames []string) {
+	usageIff(task, archiveFile == "", "A checksum can only be given together with an archive, skipping.")
+	usageIff(task, fromSource, "Both an archive and a source build given, skipping.")
+	usageIff(task, len(versionNames) > 0, "Both an archive and versions given, skipping.")
+	usageIff(task, checksum != "" && !releases.IsChecksum(checksum), "Invalid sha256 checksum %s, skipping.", checksum)
+
+	goManager := newInstallManager(task)
+	installations := goManager.Installations()
+
+	_, err := goManager.InstallArchive(archiveFile, checksum)
+	printInstalled(task, goManager, installations, err)
+}
+
+func handleInstallSource(task *tasks.Task, unstable bool, versionNames []string) {
+	usageIff(task, len(versionNames) == 0, "No versions given to install, skipping")
+
+	releaseType := releases.SelectReleaseType(unstable)
+	versionNumbers := make(version.Collection, 0, len(versionNames))
 	for _, versionName := range versionNames {
-		parsedVersion, err := version.NewVersion(versionName)
+		versionNumbers = append(versionNumbers, resolveReleaseVersion(task, versionName, releaseType))
+	}
+
+	goManager := newInstallManager(task)
+	installations := goManager.Installations()
+	printInstalled(task, goManager, installations, goManager.InstallSource(versionNumbers, releaseType))
+}
+
+// printInstalled is a function that prints the installations, that were added since the given installations were listed,
+// together with the errors that occurred while installing. If any error occurred, it exits with the exit code that belongs
+// to the errors afterwards.
+func printInstalled(task *tasks.Task, goManager *manager.GoManager, installations []manager.Installation, errs ...error) {
+	var failures []error
+	for _, err := range errs {
 		if err != nil {
-			task.FatalOnError(err)
+			failures = append(failures, err)
 		}
+	}
 
-		goManager, err := manager.NewManager(task, gomanRoot())
-		task.FatalOnError(err)
-		task.FatalOnError(goManager.Install(parsedVersion, operatingSystem, arch, releases.SelectReleaseType(unstable)))
+	if jsonOutput() {
+		printDocument(task, installDocument{
+			Installed: newInstallationDocuments(goManager.Installations(), installations, "installed"),
+			Errors:    newErrorMessages(failures),
+		})
+	} else {
+		for _, failure := range failures {
+			task.Errorf("%s", failure)
+		}
+	}
+
+	if len(failures) > 0 {
+		os.Exit(commonExitCode(failures))
+	}
+}
+
+// printUninstalled is a function that prints the given installations, that were removed since they were listed.
+func printUninstalled(task *tasks.Task, goManager *manager.GoManager, installations []manager.Installation) {
+	if jsonOutput() {
+		uninstalled := newInstallationDocuments(installations, goManager.Installations(), "uninstalled")
+		printDocument(task, uninstallDocument{Uninstalled: uninstalled})
 	}
 }
 
-func handleUninstall(task *tasks.Task, all bool, versionNames []string) {
-	root := gomanRoot()
+// newInstallManager is a function that creates a manager, which honors the checksums pinned for the working directory.
+func newInstallManager(task *tasks.Task) *manager.GoManager {
+	workingDirectory, err := os.Getwd()
+	fatalOnError(task, err)
 
-	task.FatalIff(!all && len(versionNames) == 0, "No versions to uninstall, skipping.")
-	task.FatalIff(all && len(versionNames) > 0, "Both all flag and versions given, skipping.")
+	goManager := newManager(task)
+	goManager.PinnedChecksums, err = manager.FindPinnedChecksums(workingDirectory)
+	fatalOnError(task, err)
+	if goManager.PinnedChecksums != nil {
+		task.Printf("Using checksums pinned in %s", goManager.PinnedChecksums.Path)
+	}
 
-	goManager, err := manager.NewManager(task, root)
-	task.FatalOnError(err)
+	return goManager
+}
+
+// resolveReleaseVersion is a function that turns a version name, a version constraint or 'latest' into a released version.
+func resolveReleaseVersion(task *tasks.Task, versionName string, releaseType releases.ReleaseType) *version.Version {
+	if versionName == "latest" {
+		latest, err := releases.GetLatest(releaseType)
+		fatalOnError(task, err)
+
+		return latest.GetVersionNumber()
+	}
+
+	if parsedVersion, err := version.NewVersion(versionName); err == nil {
+		return parsedVersion
+	}
+
+	constraints, err := releases.ParseConstraints(versionName)
+	fatalOnError(task, err)
+
+	release, releasePresent, err := releases.GetForConstraints(releaseType, constraints)
+	fatalOnError(task, err)
+	if !releasePresent {
+		fatalOnError(task, manager.NewError(manager.ErrReleaseNotFound, "No release matches %s", versionName))
+	}
+
+	return release.GetVersionNumber()
+}
+
+func handleUninstall(task *tasks.Task, all bool, operatingSystem, arch string, versionNames []string) {
+	usageIff(task, !all && len(versionNames) == 0, "No versions to uninstall, skipping.")
+	usageIff(task, all && len(versionNames) > 0, "Both all flag and versions given, skipping.")
+
+	goManager := newManager(task)
+	installations := goManager.Installations()
 
 	if all {
-		task.FatalOnError(goManager.UninstallAll())
+		fatalOnError(task, goManager.UninstallAll())
 	} else {
 		for _, versionName := range versionNames {
 			versionNumber, err := version.NewVersion(versionName)
-			task.FatalOnError(err)
-			task.FatalOnError(goManager.Uninstall(versionNumber))
+			fatalOnError(task, err)
+			fatalOnError(task, goManager.UninstallForPlatform(versionNumber, operatingSystem, arch))
 		}
 	}
+
+	printUninstalled(task, goManager, installations)
 }
 
 func handleSelect(task *tasks.Task, versionNames []string) {
-	task.FatalIff(len(versionNames) == 0, "No version to select, skipping.")
-	task.FatalIff(len(versionNames) > 1, "More then one version to select, skipping.")
+	usageIff(task, len(versionNames) == 0, "No version to select, skipping.")
+	usageIff(task, len(versionNames) > 1, "More then one version to select, skipping.")
+
+	goManager := newManager(task)
 
 	parsedVersion, err := version.NewVersion(versionNames[0])
 	if err != nil {
-		task.FatalOnError(err)
+		constraints, err := releases.ParseConstraints(versionNames[0])
+		fatalOnError(task, err)
+
+		var installed bool
+		parsedVersion, installed = goManager.FindInstalled(constraints)
+		if !installed {
+			fatalOnError(task, manager.NewError(manager.ErrNotInstalled, "No installed version matches %s", versionNames[0]))
+		}
 	}
 
-	goManager, err := manager.NewManager(task, gomanRoot())
-	task.FatalOnError(err)
-	task.FatalOnError(goManager.Select(parsedVersion))
+	fatalOnError(task, goManager.Select(parsedVersion))
+
+	if jsonOutput() {
+		printDocument(task, selectDocument{Selected: newInstallationDocument(manager.Installation{
+			Version:   goManager.SelectedVersion,
+			OS:        runtime.GOOS,
+			Arch:      runtime.GOARCH,
+			Directory: goManager.SDKDirectory(goManager.SelectedVersion),
+		})})
+	}
 }
 
 func handleUnselect(task *tasks.Task) {
-	goManager, err := manager.NewManager(task, gomanRoot())
-	task.FatalOnError(err)
-	task.FatalOnError(goManager.Unselect())
+	goManager := newManager(task)
+	selectedVersion := goManager.SelectedVersion
+	fatalOnError(task, goManager.Unselect())
+
+	if jsonOutput() {
+		printDocument(task, unselectDocument{Unselected: manager.VersionName(selectedVersion)})
+	}
+}
+
+func handleVerify(task *tasks.Task, versionNames []string) {
+	goManager := newManager(task)
+
+	versionNumbers := goManager.InstalledVersions
+	if len(versionNames) > 0 {
+		versionNumbers = nil
+		for _, versionName := range versionNames {
+			versionNumber, err := version.NewVersion(versionName)
+			fatalOnError(task, err)
+			versionNumbers = append(versionNumbers, versionNumber)
+		}
+	}
+	usageIff(task, len(versionNumbers) == 0, "No versions to verify, skipping.")
+
+	var failures []error
+	document := verifyDocument{Verifications: []verificationDocument{}}
+	for _, versionNumber := range versionNumbers {
+		verification := verificationDocument{Version: manager.VersionName(versionNumber), Verified: true}
+		if err := goManager.Verify(versionNumber); err != nil {
+			failures = append(failures, err)
+			verification.Verified, verification.Error = false, err.Error()
+		}
+
+		document.Verifications = append(document.Verifications, verification)
+	}
+
+	if jsonOutput() {
+		printDocument(task, document)
+	} else {
+		for _, failure := range failures {
+			task.Errorf("%s", failure)
+		}
+	}
+
+	if len(failures) > 0 {
+		os.Exit(commonExitCode(failures))
+	}
 }
 
 func handleCleanup(task *tasks.Task) {
+	goManager := newManager(task)
+	installations := goManager.Installations()
+	fatalOnError(task, goManager.Cleanup())
+
+	printUninstalled(task, goManager, installations)
+}
+
+func handleDoctor(task *tasks.Task) {
+	goManager := newManager(task)
+
+	task.Printf("Diagnosing Go installations")
+	diagnoseTask := task.Step()
+
+	problems := 0
+	document := doctorDocument{Diagnostics: []diagnosticDocument{}}
+	for _, diagnostic := range goManager.Diagnose(os.Environ(), releaseSource()) {
+		problem := diagnostic.Problem
+		diagnosticDocument := diagnosticDocument{Description: diagnostic.Description}
+		if err := diagnoseTask.Track(diagnostic.Description, func() error { return problem }); err != nil {
+			problems++
+			diagnoseTask.Step().Printf("%s", err)
+			diagnoseTask.Step().Printf("%s", diagnostic.Hint)
+			diagnosticDocument.Problem, diagnosticDocument.Hint = err.Error(), diagnostic.Hint
+		}
+
+		document.Diagnostics = append(document.Diagnostics, diagnosticDocument)
+	}
+
+	if jsonOutput() {
+		printDocument(task, document)
+		exitIff(problems > 0)
+	}
+
+	fatalIff(task, problems > 0, "Found %d problem(s)", problems)
+}
+
+func handleCacheList(task *tasks.Task) {
+	goManager := newManager(task)
+
+	archives, err := goManager.CachedArchives()
+	fatalOnError(task, err)
+
+	if jsonOutput() {
+		printDocument(task, cacheDocument{Archives: newArchiveDocuments(archives, nil)})
+		return
+	}
+
+	printResult(task, "List of cached archives:")
+	for _, archive := range archives {
+		printResult(task, "-> %s (%s)", filepath.Base(archive.Path), tasks.FormatSize(archive.Size))
+	}
+}
+
+func handleCachePrune(task *tasks.Task) {
+	goManager := newManager(task)
+	archives, err := goManager.CachedArchives()
+	fatalOnError(task, err)
+
+	fatalOnError(task, goManager.PruneCache())
+	printRemovedArchives(task, goManager, archives)
+}
+
+func handleCacheClear(task *tasks.Task) {
+	goManager := newManager(task)
+	archives, err := goManager.CachedArchives()
+	fatalOnError(task, err)
+
+	fatalOnError(task, goManager.ClearCache())
+	printRemovedArchives(task, goManager, archives)
+}
+
+// printRemovedArchives is a function that prints the given cached archives, that were removed since they were listed.
+func printRemovedArchives(task *tasks.Task, goManager *manager.GoManager, archives []manager.CachedArchive) {
+	if jsonOutput() {
+		remainingArchives, err := goManager.CachedArchives()
+		fatalOnError(task, err)
+
+		printDocument(task, cacheRemovalDocument{Removed: newArchiveDocuments(archives, remainingArchives)})
+	}
+}
+
+func handleCurrent(task *tasks.Task, pathOnly bool) {
+	goManager := newManager(task)
+
+	resolved := resolveVersion(task, goManager)
+	if jsonOutput() {
+		printDocument(task, currentDocument{
+			Version:   manager.VersionName(resolved.Version),
+			Requested: resolved.Requested,
+			Source:    resolved.Source,
+			Directory: goManager.SDKDirectory(resolved.Version),
+		})
+		return
+	}
+
+	if pathOnly {
+		printResult(task, "%s", goManager.SDKDirectory(resolved.Version))
+		return
+	}
+
+	printResult(task, "Current version: %s", resolved.Version)
+	if resolved.Source == "" {
+		printResult(task, "-> Selected as default version")
+	} else {
+		printResult(task, "-> Requested %s by %s", resolved.Requested, resolved.Source)
+	}
+	printResult(task, "-> Installed at %s", goManager.SDKDirectory(resolved.Version))
+}
+
+func handleEnv(task *tasks.Task, shellName string, hook bool) {
+	shell := shellutil.DetectShell()
+	if shellName != "" {
+		var err error
+		shell, err = shellutil.ParseShell(shellName)
+		fatalOnError(task, err)
+	}
+
+	goManager := newManager(task)
+
+	if jsonOutput() {
+		printDocument(task, envDocument{
+			Variables: map[string]string{"GMNROOT": goManager.RootDirectory, "GOROOT": goManager.SelectedDirectory()},
+			Path:      []string{goManager.ShimDirectory(), filepath.Join(goManager.SelectedDirectory(), "bin")},
+		})
+		return
+	}
+
+	printResult(task, "%s", shell.SetVariable("GMNROOT", goManager.RootDirectory))
+	printResult(task, "%s", shell.SetVariable("GOROOT", goManager.SelectedDirectory()))
+	printResult(task, "%s", shell.PrependPath(goManager.ShimDirectory(), filepath.Join(goManager.SelectedDirectory(), "bin")))
+
+	if hook {
+		executable, err := os.Executable()
+		fatalOnError(task, err)
+
+		printResult(task, "%s", shell.DirectoryHook([]string{executable, "current", "-path"}, goManager.SelectedDirectory()))
+	}
+}
+
+func handleWhich(task *tasks.Task, tools []string) {
+	usageIff(task, len(tools) > 1, "More then one tool to locate, skipping.")
+
+	tool := "go"
+	if len(tools) == 1 {
+		tool = tools[0]
+	}
+	if runtime.GOOS == "windows" {
+		tool += ".exe"
+	}
+
+	goManager := newManager(task)
+
+	resolved := resolveVersion(task, goManager)
+
+	sdkDirectory := goManager.SDKDirectory(resolved.Version)
+	toolPath := filepath.Join(sdkDirectory, "bin", tool)
+	fatalIff(task, !fileutil.PathExists(toolPath), "Tool %s does not exist in %s", tool, sdkDirectory)
+
+	if jsonOutput() {
+		printDocument(task, whichDocument{Tool: tool, Path: toolPath, Version: manager.VersionName(resolved.Version)})
+		return
+	}
+
+	printResult(task, "%s", toolPath)
+}
+
+func handleExec(task *tasks.Task, args []string) {
+	usageIff(task, len(args) == 0, "No version to execute with, skipping.")
+
+	parsedVersion, err := version.NewVersion(args[0])
+	fatalOnError(task, err)
+
+	command := args[1:]
+	if len(command) > 0 && command[0] == "--" {
+		command = command[1:]
+	}
+	usageIff(task, len(command) == 0, "No command to execute, skipping.")
+
+	goManager := newManager(task)
+
+	exitOnCommandError(task, goManager.Exec(parsedVersion, command))
+}
+
+func handleShim(task *tasks.Task) {
+	executable, err := os.Executable()
+	fatalOnError(task, err)
+
+	goManager := newManager(task)
+	fatalOnError(task, goManager.InstallShims(executable))
+
+	task.Printf("Add %s to the beginning of your PATH to use the shims", goManager.ShimDirectory())
+
+	if jsonOutput() {
+		printDocument(task, shimDocument{Directory: goManager.ShimDirectory()})
+	}
+}
+
+func handleShimCall(task *tasks.Task, tool string, args []string) {
+	workingDirectory, err := os.Getwd()
+	fatalOnError(task, err)
+
+	goManager := newManager(task)
+
+	exitOnCommandError(task, goManager.RunShim(tool, args, workingDirectory))
+}
+
+func exitOnCommandError(task *tasks.Task, err error) {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		os.Exit(exitErr.ExitCode())
+	}
+
+	fatalOnError(task, err)
+}
+
+func resolveVersion(task *tasks.Task, goManager *manager.GoManager) *manager.ResolvedVersion {
+	workingDirectory, err := os.Getwd()
+	fatalOnError(task, err)
+
+	resolved, err := goManager.Resolve(workingDirectory)
+	fatalOnError(task, err)
+
+	return resolved
+}
+
+func releaseSource() releases.ReleaseSource {
+	if releaseFile := os.Getenv("GMNRELEASEFILE"); releaseFile != "" {
+		return &releases.FileSource{Path: releaseFile, FileBaseURL: os.Getenv("GMNFILEMIRROR")}
+	}
+
+	source := releases.OfficialSource()
+	if mirror := os.Getenv("GMNMIRROR"); mirror != "" {
+		source.BaseURL = mirror
+	}
+	source.FileBaseURL = os.Getenv("GMNFILEMIRROR")
+
+	return source
+}
+
+func gitRemote() string {
+	if remote := os.Getenv("GMNGITREMOTE"); remote != "" {
+		return remote
+	}
+
+	return manager.DefaultGitRemote
+}
+
+func checksumBaseURL() string {
+	// While offline, no checksum files can be retrieved, so archives are only verified against the cached release list.
+	if *rootOffline {
+		return ""
+	}
+
+	switch checksumMirror := os.Getenv("GMNCHECKSUMMIRROR"); checksumMirror {
+	case "":
+		return releases.OfficialChecksumBaseURL
+	case "off":
+		return ""
+	default:
+		return checksumMirror
+	}
+}
+
+func newManager(task *tasks.Task) *manager.GoManager {
 	goManager, err := manager.NewManager(task, gomanRoot())
-	task.FatalOnError(err)
-	task.FatalOnError(goManager.Cleanup())
+	fatalOnError(task, err)
+
+	goManager.CacheDirectory = cacheDirectory()
+	goManager.LockTimeout = *rootLockTimeout
+	return goManager
+}
+
+func cacheDirectory() string {
+	cache := os.Getenv("GMNCACHE")
+	if len(cache) > 0 {
+		return cache
+	}
+
+	return filepath.Join(gomanRoot(), "cache")
 }
 
 func gomanRoot() string {
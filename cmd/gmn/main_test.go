@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jangraefen/go-man/internal/shellutil"
+	"github.com/jangraefen/go-man/pkg/manager"
+	"github.com/jangraefen/go-man/pkg/releases"
+	"github.com/jangraefen/go-man/pkg/tasks"
+)
+
+func TestChecksumBaseURL(t *testing.T) {
+	t.Cleanup(func() {
+		*rootOffline = false
+		_ = os.Unsetenv("GMNCHECKSUMMIRROR")
+	})
+
+	assert.Equal(t, releases.OfficialChecksumBaseURL, checksumBaseURL())
+
+	_ = os.Setenv("GMNCHECKSUMMIRROR", "https://mirror.example.org/go/")
+	assert.Equal(t, "https://mirror.example.org/go/", checksumBaseURL())
+
+	_ = os.Setenv("GMNCHECKSUMMIRROR", "off")
+	assert.Empty(t, checksumBaseURL())
+
+	// While offline, no checksum files are retrieved regardless of the mirror.
+	_ = os.Setenv("GMNCHECKSUMMIRROR", "https://mirror.example.org/go/")
+	*rootOffline = true
+	assert.Empty(t, checksumBaseURL())
+}
+
+func TestResults_WithLogFormats(t *testing.T) {
+	rootDirectory := t.TempDir()
+	sdkDirectory := filepath.Join(rootDirectory, "go1.15.2")
+	require.NoError(t, os.MkdirAll(filepath.Join(sdkDirectory, "bin"), 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(sdkDirectory, "VERSION"), []byte("go1.15.2"), 0600))
+
+	tool := "go"
+	if runtime.GOOS == "windows" {
+		tool += ".exe"
+	}
+	toolPath := filepath.Join(sdkDirectory, "bin", tool)
+	require.NoError(t, ioutil.WriteFile(toolPath, []byte("tool"), 0700))
+
+	logFormat := *rootLog
+	t.Cleanup(func() {
+		*rootLog = logFormat
+		stdout = os.Stdout
+		_ = os.Unsetenv("GMNROOT")
+		_ = os.Unsetenv(manager.VersionVariable)
+	})
+	require.NoError(t, os.Setenv("GMNROOT", rootDirectory))
+	require.NoError(t, os.Setenv(manager.VersionVariable, "1.15.2"))
+
+	// Results have to be printed regardless of the log format, since scripts and the shell hook consume them.
+	for _, format := range []string{logQuiet, logJSON} {
+		t.Run(format, func(t *testing.T) {
+			*rootLog = format
+			log := &bytes.Buffer{}
+			task := &tasks.Task{Output: log, Error: log}
+			configureOutput(task)
+
+			output := &bytes.Buffer{}
+			stdout = output
+
+			handleEnv(task, string(shellutil.Bash), false)
+			assert.Contains(t, output.String(), shellutil.Bash.SetVariable("GMNROOT", rootDirectory)+"\n")
+
+			output.Reset()
+			handleWhich(task, nil)
+			assert.Equal(t, toolPath+"\n", output.String())
+
+			output.Reset()
+			handleCurrent(task, true)
+			assert.Equal(t, sdkDirectory+"\n", output.String())
+		})
+	}
+}
@@ -0,0 +1,326 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jangraefen/go-man/pkg/manager"
+	"github.com/jangraefen/go-man/pkg/releases"
+	"github.com/jangraefen/go-man/pkg/tasks"
+)
+
+const (
+	outputText = "text"
+	outputJSON = "json"
+
+	logAuto    = "auto"
+	logTree    = "tree"
+	logPlain   = "plain"
+	logVerbose = "verbose"
+	logQuiet   = "quiet"
+	logJSON    = "json"
+)
+
+// The document types describe the JSON documents, that are printed instead of the text output, if the JSON output format is
+// chosen. Their fields must only ever be added, since scripts depend on them.
+type (
+	errorDocument struct {
+		Error string `json:"error"`
+	}
+
+	listDocument struct {
+		Versions []versionDocument `json:"versions"`
+	}
+
+	versionDocument struct {
+		Version   string         `json:"version"`
+		Line      string         `json:"line"`
+		Released  bool           `json:"released"`
+		Installed bool           `json:"installed"`
+		Selected  bool           `json:"selected"`
+		Directory string         `json:"directory,omitempty"`
+		Files     []fileDocument `json:"files,omitempty"`
+	}
+
+	fileDocument struct {
+		Filename string `json:"filename"`
+		OS       string `json:"os,omitempty"`
+		Arch     string `json:"arch,omitempty"`
+		Kind     string `json:"kind"`
+		Sha256   string `json:"sha256"`
+		Size     int64  `json:"size"`
+	}
+
+	installationDocument struct {
+		Version   string        `json:"version"`
+		OS        string        `json:"os"`
+		Arch      string        `json:"arch"`
+		Directory string        `json:"directory"`
+		Action    string        `json:"action,omitempty"`
+		Size      int64         `json:"size,omitempty"`
+		Archive   *fileDocument `json:"archive,omitempty"`
+	}
+
+	installDocument struct {
+		Installed []installationDocument `json:"installed"`
+		Errors    []string               `json:"errors,omitempty"`
+	}
+
+	uninstallDocument struct {
+		Uninstalled []installationDocument `json:"uninstalled"`
+	}
+
+	selectDocument struct {
+		Selected installationDocument `json:"selected"`
+	}
+
+	unselectDocument struct {
+		Unselected string `json:"unselected"`
+	}
+
+	verifyDocument struct {
+		Verifications []verificationDocument `json:"verifications"`
+	}
+
+	verificationDocument struct {
+		Version  string `json:"version"`
+		Verified bool   `json:"verified"`
+		Error    string `json:"error,omitempty"`
+	}
+
+	doctorDocument struct {
+		Diagnostics []diagnosticDocument `json:"diagnostics"`
+	}
+
+	diagnosticDocument struct {
+		Description string `json:"description"`
+		Problem     string `json:"problem,omitempty"`
+		Hint        string `json:"hint,omitempty"`
+	}
+
+	cacheDocument struct {
+		Archives []archiveDocument `json:"archives"`
+	}
+
+	cacheRemovalDocument struct {
+		Removed []archiveDocument `json:"removed"`
+	}
+
+	archiveDocument struct {
+		Path    string `json:"path"`
+		Sha256  string `json:"sha256"`
+		Size    int64  `json:"size"`
+		Version string `json:"version,omitempty"`
+	}
+
+	currentDocument struct {
+		Version   string `json:"version"`
+		Requested string `json:"requested,omitempty"`
+		Source    string `json:"source,omitempty"`
+		Directory string `json:"directory"`
+	}
+
+	whichDocument struct {
+		Tool    string `json:"tool"`
+		Path    string `json:"path"`
+		Version string `json:"version"`
+	}
+
+	shimDocument struct {
+		Directory string `json:"directory"`
+	}
+
+	envDocument struct {
+		Variables map[string]string `json:"variables"`
+		Path      []string          `json:"path"`
+	}
+)
+
+// stdout is the writer that results are printed to. Unlike the log, results are never passed to the reporter of a task, so
+// that the chosen log format cannot suppress or alter them.
+var stdout io.Writer = os.Stdout
+
+// jsonOutput is a function that checks if the JSON output format was chosen.
+func jsonOutput() bool {
+	return *rootOutput == outputJSON
+}
+
+// configureOutput is a function that prepares a task for the chosen output format and log format. For the JSON output
+// format, the log is written to the stderr, since the stdout is reserved for the documents. Unless a log format is chosen
+// explicitly, no log is written at all in that case. Errors are then printed as error documents.
+func configureOutput(task *tasks.Task) {
+	logFormat, output := *rootLog, task.Output
+	if jsonOutput() {
+		output = task.Error
+		if logFormat == logAuto {
+			logFormat = logQuiet
+		}
+	}
+
+	switch logFormat {
+	case logTree:
+		task.Reporter = tasks.NewTreeReporter(output, task.Error)
+	case logPlain:
+		task.Reporter = tasks.NewPlainReporter(output, task.Error)
+	case logVerbose:
+		task.Reporter = tasks.NewVerboseReporter(output, task.Error)
+	case logQuiet:
+		task.Reporter = tasks.NewQuietReporter(task.Error)
+	case logJSON:
+		task.Reporter = tasks.NewJSONReporter(output)
+	default:
+		task.Reporter = tasks.NewReporter(output, task.Error)
+	}
+
+	if jsonOutput() {
+		task.Reporter = &documentReporter{Reporter: task.Reporter, output: stdout}
+	}
+}
+
+// printResult is a function that prints a line of the text output to the stdout. It provides the same formatting as the fmt
+// package does.
+func printResult(task *tasks.Task, format string, args ...interface{}) {
+	_, err := fmt.Fprintf(stdout, format+"\n", args...)
+	fatalOnError(task, err)
+}
+
+// printDocument is a function that prints a JSON document to the stdout.
+func printDocument(task *tasks.Task, document interface{}) {
+	encoder := json.NewEncoder(stdout)
+	encoder.SetIndent("", "  ")
+	fatalOnError(task, encoder.Encode(document))
+}
+
+// documentReporter is a reporter that prints the messages of errors as error documents and passes everything else
+// to another reporter.
+type documentReporter struct {
+	tasks.Reporter
+	output io.Writer
+}
+
+func (r *documentReporter) Error(_ uint, message string) {
+	content, err := json.Marshal(errorDocument{Error: message})
+	if err != nil {
+		return
+	}
+
+	_, _ = r.output.Write(append(content, '\n'))
+}
+
+func newVersionDocuments(listed []manager.ListedVersion) []versionDocument {
+	documents := make([]versionDocument, 0, len(listed))
+
+	for _, line := range manager.GroupByReleaseLine(listed) {
+		for _, listedVersion := range line.Versions {
+			documents = append(documents, versionDocument{
+				Version:   listedVersion.Name,
+				Line:      line.Name,
+				Released:  listedVersion.Released,
+				Installed: listedVersion.Installed,
+				Selected:  listedVersion.Selected,
+				Directory: listedVersion.Directory,
+				Files:     newFileDocuments(listedVersion.Files),
+			})
+		}
+	}
+
+	return documents
+}
+
+func newFileDocuments(files []releases.ReleaseFile) []fileDocument {
+	var documents []fileDocument
+
+	for _, file := range files {
+		documents = append(documents, newFileDocument(file))
+	}
+
+	return documents
+}
+
+func newFileDocument(file releases.ReleaseFile) fileDocument {
+	return fileDocument{
+		Filename: file.Filename,
+		OS:       file.OS,
+		Arch:     file.Arch,
+		Kind:     string(file.Kind),
+		Sha256:   file.Sha256,
+		Size:     int64(file.Size),
+	}
+}
+
+// newInstallationDocument is a function that describes an installation. If its manifest is available, the size of the
+// installation and the archive that it was installed from are described as well.
+func newInstallationDocument(installation manager.Installation) installationDocument {
+	document := installationDocument{
+		Version:   manager.VersionName(installation.Version),
+		OS:        installation.OS,
+		Arch:      installation.Arch,
+		Directory: installation.Directory,
+	}
+
+	if manifest, err := installation.Manifest(); err == nil {
+		document.Size = manifest.Size()
+		if manifest.Archive.Filename != "" {
+			archive := newFileDocument(manifest.Archive)
+			document.Archive = &archive
+		}
+	}
+
+	return document
+}
+
+// newInstallationDocuments is a function that describes all installations, that are only part of the first given list, as
+// the result of the given action.
+func newInstallationDocuments(installations, others []manager.Installation, action string) []installationDocument {
+	documents := []installationDocument{}
+
+	for _, installation := range installations {
+		contained := false
+		for _, other := range others {
+			contained = contained || other.Directory == installation.Directory
+		}
+
+		if !contained {
+			document := newInstallationDocument(installation)
+			document.Action = action
+			documents = append(documents, document)
+		}
+	}
+
+	return documents
+}
+
+// newErrorMessages is a function that describes the given errors by their messages. Might be nil, if there are no errors.
+func newErrorMessages(errs []error) []string {
+	var messages []string
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+
+	return messages
+}
+
+// newArchiveDocuments is a function that describes all cached archives, that are only part of the first given list.
+func newArchiveDocuments(archives, others []manager.CachedArchive) []archiveDocument {
+	documents := []archiveDocument{}
+
+	for _, archive := range archives {
+		contained := false
+		for _, other := range others {
+			contained = contained || other.Path == archive.Path
+		}
+		if contained {
+			continue
+		}
+
+		document := archiveDocument{Path: archive.Path, Sha256: archive.Sha256, Size: archive.Size}
+		if archive.Version != nil {
+			document.Version = manager.VersionName(archive.Version)
+		}
+		documents = append(documents, document)
+	}
+
+	return documents
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jangraefen/go-man/pkg/manager"
+)
+
+func TestNewInstallationDocuments(t *testing.T) {
+	tempDir := t.TempDir()
+	installed := manager.Installation{
+		Version:   version.Must(version.NewVersion("1.15.2")),
+		OS:        "linux",
+		Arch:      "amd64",
+		Directory: filepath.Join(tempDir, "go1.15.2"),
+	}
+	kept := manager.Installation{
+		Version:   version.Must(version.NewVersion("1.16.0")),
+		OS:        "windows",
+		Arch:      "amd64",
+		Directory: filepath.Join(tempDir, "go1.16.windows-amd64"),
+	}
+
+	manifest := `{
+		"version": "go1.15.2",
+		"archive": {"filename": "go1.15.2.linux-amd64.tar.gz", "os": "linux", "arch": "amd64", "sha256": "1111", "size": 42,
+			"kind": "archive"},
+		"files": [{"path": "VERSION", "size": 8}, {"path": "bin/go", "size": 4}]
+	}`
+	require.NoError(t, ioutil.WriteFile(installed.Directory+".manifest.json", []byte(manifest), 0600))
+
+	documents := newInstallationDocuments([]manager.Installation{installed, kept}, []manager.Installation{kept}, "installed")
+	assert.Equal(t, []installationDocument{{
+		Version:   "1.15.2",
+		OS:        "linux",
+		Arch:      "amd64",
+		Directory: installed.Directory,
+		Action:    "installed",
+		Size:      12,
+		Archive: &fileDocument{
+			Filename: "go1.15.2.linux-amd64.tar.gz",
+			OS:       "linux",
+			Arch:     "amd64",
+			Kind:     "archive",
+			Sha256:   "1111",
+			Size:     42,
+		},
+	}}, documents)
+
+	// Without a manifest, neither the size nor the archive are known.
+	installations := []manager.Installation{installed, kept}
+	documents = newInstallationDocuments(installations, []manager.Installation{installed}, "uninstalled")
+	assert.Equal(t, []installationDocument{{
+		Version:   "1.16",
+		OS:        "windows",
+		Arch:      "amd64",
+		Directory: kept.Directory,
+		Action:    "uninstalled",
+	}}, documents)
+
+	documents = newInstallationDocuments(nil, []manager.Installation{installed}, "installed")
+	assert.NotNil(t, documents)
+	assert.Empty(t, documents)
+}
+
+func TestNewArchiveDocuments(t *testing.T) {
+	known := manager.CachedArchive{
+		Path:    "/cache/archives/1111/go1.15.2.linux-amd64.tar.gz",
+		Sha256:  "1111",
+		Size:    42,
+		Version: version.Must(version.NewVersion("1.15.2")),
+	}
+	unknown := manager.CachedArchive{
+		Path:   "/cache/archives/2222/unknown.zip",
+		Sha256: "2222",
+		Size:   7,
+	}
+
+	documents := newArchiveDocuments([]manager.CachedArchive{known, unknown}, nil)
+	assert.Equal(t, []archiveDocument{
+		{Path: known.Path, Sha256: "1111", Size: 42, Version: "1.15.2"},
+		{Path: unknown.Path, Sha256: "2222", Size: 7},
+	}, documents)
+
+	documents = newArchiveDocuments([]manager.CachedArchive{known, unknown}, []manager.CachedArchive{known})
+	assert.Equal(t, []archiveDocument{{Path: unknown.Path, Sha256: "2222", Size: 7}}, documents)
+
+	documents = newArchiveDocuments(nil, nil)
+	assert.NotNil(t, documents)
+	assert.Empty(t, documents)
+}
+
+func TestNewErrorMessages(t *testing.T) {
+	assert.Nil(t, newErrorMessages(nil))
+	assert.Equal(t, []string{"first", "second"}, newErrorMessages([]error{errors.New("first"), errors.New("second")}))
+}
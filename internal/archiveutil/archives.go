@@ -1,6 +1,15 @@
 package archiveutil
 
 import (
+	"archive/tar"
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
 	"github.com/mholt/archiver/v3"
 
 	"github.com/jangraefen/go-man/internal/fileutil"
@@ -11,10 +20,230 @@ import (
 // The functions returns false if nothing was done because it should not overwrite and the destination directory already
 // existed.
 func Extract(archiveFile, destinationDirection string, overwrite bool) (bool, error) {
+	return ExtractWithProgress(archiveFile, destinationDirection, overwrite, nil)
+}
+
+// ExtractWithProgress is a function that extracts any given archive file into a given destination directory, like Extract
+// does. While extracting, the amount of bytes that were read from the archive file so far is reported to a given function.
+func ExtractWithProgress(archiveFile, destinationDirection string, overwrite bool, progress func(done int64)) (bool, error) {
 	if fileutil.PathExists(destinationDirection) && !overwrite {
 		return false, nil
 	}
 
 	fileutil.TryRemove(destinationDirection)
-	return true, archiver.Unarchive(archiveFile, destinationDirection)
+	return true, unarchive(archiveFile, destinationDirection, progress)
+}
+
+func unarchive(archiveFile, destinationDirectory string, progress func(done int64)) error {
+	format, err := archiver.ByExtension(archiveFile)
+	if err != nil {
+		return err
+	}
+
+	reader, ok := format.(archiver.Reader)
+	if !ok {
+		return fmt.Errorf("format %s cannot be extracted", format)
+	}
+
+	file, err := os.Open(archiveFile) //nolint:gosec
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	if err := reader.Open(&progressReader{file: file, progress: progress}, fileInfo.Size()); err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	if err := os.MkdirAll(destinationDirectory, 0755); err != nil {
+		return err
+	}
+
+	for {
+		entry, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		err = extractEntry(entry, destinationDirectory)
+		_ = entry.Close()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func extractEntry(entry archiver.File, destinationDirectory string) error {
+	var name, linkTarget string
+	var hardLink bool
+	switch header := entry.Header.(type) {
+	case *tar.Header:
+		name, linkTarget, hardLink = header.Name, header.Linkname, header.Typeflag == tar.TypeLink
+	case zip.FileHeader:
+		name = header.Name
+	default:
+		return fmt.Errorf("unsupported archive entry %s", entry.Name())
+	}
+
+	target, err := entryPath(destinationDirectory, name)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case hardLink:
+		// The target of a hard link is named relative to the root of the archive and has to be a file that was extracted
+		// before.
+		linkSource, err := entryPath(destinationDirectory, linkTarget)
+		if err != nil {
+			return err
+		}
+		if fileInfo, err := os.Lstat(linkSource); err != nil || !fileInfo.Mode().IsRegular() {
+			return fmt.Errorf("archive entry %s links to %s, which is not a regular file", name, linkTarget)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		return os.Link(linkSource, target)
+	case entry.IsDir():
+		return os.MkdirAll(target, 0755)
+	case entry.Mode()&os.ModeSymlink != 0:
+		if linkTarget == "" {
+			// Zip archives store the target of a symbolic link as the content of the entry.
+			content, err := ioutil.ReadAll(entry)
+			if err != nil {
+				return err
+			}
+			linkTarget = strings.TrimSpace(string(content))
+		}
+
+		if err := checkLinkTarget(destinationDirectory, target, linkTarget); err != nil {
+			return fmt.Errorf("archive entry %s: %w", name, err)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		return os.Symlink(linkTarget, target)
+	case entry.Mode().IsRegular():
+		return writeFile(target, entry, entry.Mode().Perm())
+	default:
+		return fmt.Errorf("archive entry %s has an unsupported type", name)
+	}
+}
+
+// entryPath is a function that returns the path that an archive entry is extracted to. Entries must neither escape the
+// destination directory, for example by containing a path like "../../etc/passwd", nor be written through a symbolic
+// link that was extracted before, since it might point anywhere.
+func entryPath(destinationDirectory, name string) (string, error) {
+	target := filepath.Join(destinationDirectory, filepath.FromSlash(name))
+	if !isWithin(destinationDirectory, target) {
+		return "", fmt.Errorf("archive entry %s points outside of the destination directory", name)
+	}
+
+	for path := target; isWithin(destinationDirectory, path) && path != filepath.Clean(destinationDirectory); {
+		if fileInfo, err := os.Lstat(path); err == nil && fileInfo.Mode()&os.ModeSymlink != 0 {
+			return "", fmt.Errorf("archive entry %s would be written through the symbolic link %s", name, path)
+		}
+
+		path = filepath.Dir(path)
+	}
+
+	return target, nil
+}
+
+// checkLinkTarget is a function that checks if the target of a symbolic link stays inside of the destination directory.
+// Parent references are only allowed at the beginning of the target, since a reference that follows another symbolic
+// link would be resolved relative to wherever that link points to, which cannot be checked lexically.
+func checkLinkTarget(destinationDirectory, linkPath, linkTarget string) error {
+	linkTarget = filepath.FromSlash(linkTarget)
+	if linkTarget == "" || filepath.IsAbs(linkTarget) || filepath.VolumeName(linkTarget) != "" ||
+		strings.HasPrefix(linkTarget, string(filepath.Separator)) {
+		return fmt.Errorf("symbolic link to %s is not relative", linkTarget)
+	}
+
+	descended := false
+	for _, segment := range strings.Split(linkTarget, string(filepath.Separator)) {
+		switch segment {
+		case "", ".":
+		case "..":
+			if descended {
+				return fmt.Errorf("symbolic link to %s contains a parent reference after its beginning", linkTarget)
+			}
+		default:
+			descended = true
+		}
+	}
+
+	if !isWithin(destinationDirectory, filepath.Join(filepath.Dir(linkPath), linkTarget)) {
+		return fmt.Errorf("symbolic link to %s points outside of the destination directory", linkTarget)
+	}
+
+	return nil
+}
+
+func isWithin(directory, path string) bool {
+	relative, err := filepath.Rel(directory, path)
+	return err == nil && relative != ".." && !strings.HasPrefix(relative, ".."+string(filepath.Separator))
+}
+
+func writeFile(target string, content io.Reader, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(file, content); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// progressReader is a reader that reports the amount of bytes that were read from a file.
+// Since zip archives are read randomly, it supports both sequential and random access.
+type progressReader struct {
+	file     *os.File
+	done     int64
+	progress func(done int64)
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.file.Read(p)
+	r.report(n)
+	return n, err
+}
+
+func (r *progressReader) ReadAt(p []byte, offset int64) (int, error) {
+	n, err := r.file.ReadAt(p, offset)
+	r.report(n)
+	return n, err
+}
+
+func (r *progressReader) report(n int) {
+	r.done += int64(n)
+	if r.progress != nil {
+		r.progress(r.done)
+	}
 }
@@ -1,11 +1,16 @@
 package archiveutil
 
 import (
+	"archive/tar"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
 	"path/filepath"
 	"runtime"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/jangraefen/go-man/internal/fileutil"
 )
@@ -37,6 +42,143 @@ func TestExtractArchive(t *testing.T) {
 	assert.True(t, extracted)
 }
 
+func TestExtractWithProgress(t *testing.T) {
+	archiveFile := getTestFile(t, "valid.zip")
+	destinationDirectory := filepath.Join(t.TempDir(), "extracted")
+
+	var reported []int64
+	extracted, err := ExtractWithProgress(archiveFile, destinationDirectory, false, func(done int64) {
+		reported = append(reported, done)
+	})
+	assert.NoError(t, err)
+	assert.True(t, extracted)
+	require.NotEmpty(t, reported)
+	assert.Greater(t, reported[len(reported)-1], int64(0))
+}
+
+func TestExtractWithProgress_TarGz(t *testing.T) {
+	tempDir := t.TempDir()
+	archiveFile := filepath.Join(tempDir, "archive.tar.gz")
+	destinationDirectory := filepath.Join(tempDir, "extracted")
+
+	writeTarGz(t, archiveFile, []*tar.Header{
+		{Name: "go/", Typeflag: tar.TypeDir, Mode: 0755},
+		{Name: "go/VERSION", Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len("go1.15.2"))},
+		{Name: "go/LINK", Typeflag: tar.TypeSymlink, Linkname: "VERSION"},
+	})
+
+	fileInfo, err := os.Stat(archiveFile)
+	require.NoError(t, err)
+
+	var done int64
+	extracted, err := ExtractWithProgress(archiveFile, destinationDirectory, false, func(d int64) { done = d })
+	assert.NoError(t, err)
+	assert.True(t, extracted)
+	assert.Equal(t, fileInfo.Size(), done)
+
+	content, err := ioutil.ReadFile(filepath.Join(destinationDirectory, "go", "VERSION"))
+	assert.NoError(t, err)
+	assert.Equal(t, "go1.15.2", string(content))
+
+	if runtime.GOOS != "windows" {
+		linkTarget, err := os.Readlink(filepath.Join(destinationDirectory, "go", "LINK"))
+		assert.NoError(t, err)
+		assert.Equal(t, "VERSION", linkTarget)
+	}
+
+	writeTarGz(t, archiveFile, []*tar.Header{
+		{Name: "../escaped", Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len("go1.15.2"))},
+	})
+
+	extracted, err = ExtractWithProgress(archiveFile, destinationDirectory, true, nil)
+	assert.Error(t, err)
+	assert.True(t, extracted)
+	assert.NoFileExists(t, filepath.Join(tempDir, "escaped"))
+}
+
+func TestExtractWithProgress_WithLinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symbolic links require special privileges on windows")
+	}
+
+	tempDir := t.TempDir()
+	archiveFile := filepath.Join(tempDir, "archive.tar.gz")
+	destinationDirectory := filepath.Join(tempDir, "extracted")
+	outsideDirectory := filepath.Join(tempDir, "outside")
+	require.NoError(t, os.Mkdir(outsideDirectory, 0700))
+
+	writeTarGz(t, archiveFile, []*tar.Header{
+		{Name: "go/VERSION", Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len("go1.15.2"))},
+		{Name: "go/bin/VERSION", Typeflag: tar.TypeSymlink, Linkname: "../VERSION"},
+		{Name: "go/HARDLINK", Typeflag: tar.TypeLink, Linkname: "go/VERSION"},
+	})
+
+	extracted, err := ExtractWithProgress(archiveFile, destinationDirectory, true, nil)
+	assert.NoError(t, err)
+	assert.True(t, extracted)
+
+	for _, linkName := range []string{filepath.Join("bin", "VERSION"), "HARDLINK"} {
+		content, err := ioutil.ReadFile(filepath.Join(destinationDirectory, "go", linkName))
+		assert.NoError(t, err)
+		assert.Equal(t, "go1.15.2", string(content))
+	}
+
+	maliciousArchives := [][]*tar.Header{
+		{
+			{Name: "go/link", Typeflag: tar.TypeSymlink, Linkname: outsideDirectory},
+			{Name: "go/link/escaped", Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len("go1.15.2"))},
+		},
+		{
+			{Name: "go/link", Typeflag: tar.TypeSymlink, Linkname: "../../outside"},
+		},
+		{
+			{Name: "go/link", Typeflag: tar.TypeSymlink, Linkname: "."},
+			{Name: "go/escape", Typeflag: tar.TypeSymlink, Linkname: "link/../.."},
+		},
+		{
+			{Name: "go/dir", Typeflag: tar.TypeSymlink, Linkname: "."},
+			{Name: "go/dir/escaped", Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len("go1.15.2"))},
+		},
+		{
+			{Name: "go/HARDLINK", Typeflag: tar.TypeLink, Linkname: "../outside/file"},
+		},
+	}
+
+	for _, headers := range maliciousArchives {
+		writeTarGz(t, archiveFile, headers)
+
+		extracted, err = ExtractWithProgress(archiveFile, destinationDirectory, true, nil)
+		assert.Error(t, err, headers[len(headers)-1].Name)
+		assert.True(t, extracted)
+
+		outsideFiles, err := ioutil.ReadDir(outsideDirectory)
+		require.NoError(t, err)
+		assert.Empty(t, outsideFiles)
+	}
+}
+
+func writeTarGz(t *testing.T, archiveFile string, headers []*tar.Header) {
+	t.Helper()
+
+	file, err := os.Create(archiveFile)
+	require.NoError(t, err)
+
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	for _, header := range headers {
+		require.NoError(t, tarWriter.WriteHeader(header))
+		if header.Typeflag == tar.TypeReg {
+			_, err := tarWriter.Write([]byte("go1.15.2"))
+			require.NoError(t, err)
+		}
+	}
+
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+	require.NoError(t, file.Close())
+}
+
 func getTestFile(t *testing.T, fileName string) string {
 	t.Helper()
 
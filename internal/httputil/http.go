@@ -2,84 +2,345 @@ package httputil
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
+	neturl "net/url"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/jangraefen/go-man/internal/fileutil"
 )
 
+const (
+	// PartialSuffix is the suffix of the file, that an unfinished download is written to.
+	PartialSuffix = ".partial"
+)
+
 var (
 	// Client holds the HTTP client object that is used by the HTTP utils to make HTTP calls.
 	// By default, the http.DefaultClient is used, but this can be changed if needed.
 	Client = http.DefaultClient
+	// Timeout is the duration that a request may stall without receiving any data, before it is aborted.
+	Timeout = 30 * time.Second
+	// Retries is the number of times that a download is retried, if it failed with a transient error.
+	Retries = 5
+	// RetryBackoff is the delay before the first retry of a download. The delay is doubled with each further retry.
+	RetryBackoff = time.Second
+
+	errStalled       = errors.New("no data received before timeout")
+	errRangeMismatch = errors.New("server responded with a range that does not continue the partial download")
 )
 
+// Validators is a struct that holds the validators of an HTTP response, which allow to revalidate a cached copy of it.
+type Validators struct {
+	// The entity tag that identifies the version of the response.
+	ETag string `json:"etag,omitempty"`
+	// The date that the response was last modified at.
+	LastModified string `json:"lastModified,omitempty"`
+}
+
 // GetJSON is a function that reads a JSON document from a given URL and marshals that into a given result object.
 func GetJSON(url string, result interface{}) error {
-	response, err := Client.Get(url) //nolint:gosec
+	_, _, err := GetJSONIfModified(url, Validators{}, result)
+	return err
+}
+
+// GetJSONIfModified is a function that reads a JSON document from a given URL, if it differs from a cached copy.
+// The validators of the cached copy are sent as a conditional request. If the server responds that the document was not
+// modified, the result object is left untouched and false is returned. Otherwise, the document is marshaled into the result
+// object and returned together with its new validators.
+func GetJSONIfModified(url string, validators Validators, result interface{}) (Validators, bool, error) {
+	request, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
-		return err
+		return Validators{}, false, err
+	}
+	if validators.ETag != "" {
+		request.Header.Set("If-None-Match", validators.ETag)
+	}
+	if validators.LastModified != "" {
+		request.Header.Set("If-Modified-Since", validators.LastModified)
+	}
+
+	response, body, err := doWithStallTimeout(request)
+	if err != nil {
+		return Validators{}, false, err
+	}
+
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	if response.StatusCode == http.StatusNotModified {
+		return validators, false, nil
 	}
 	if response.StatusCode != 200 {
-		return fmt.Errorf("unexpected status while retrieving releases: %s", response.Status)
+		return Validators{}, false, fmt.Errorf("unexpected status while retrieving releases: %s", response.Status)
+	}
+
+	if err := json.NewDecoder(body).Decode(&result); err != nil {
+		return Validators{}, false, err
+	}
+
+	return Validators{
+		ETag:         response.Header.Get("ETag"),
+		LastModified: response.Header.Get("Last-Modified"),
+	}, true, nil
+}
+
+// GetText is a function that reads a small text document from a given URL, like a checksum file.
+// At most the given amount of bytes is read, to protect against endpoints that serve something else than expected.
+func GetText(url string, limit int64) (string, error) {
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	response, body, err := doWithStallTimeout(request)
+	if err != nil {
+		return "", err
 	}
 
 	defer func() {
 		_ = response.Body.Close()
 	}()
 
-	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
-		return err
+	if response.StatusCode != 200 {
+		return "", fmt.Errorf("unexpected status while retrieving %s: %s", url, response.Status)
 	}
 
-	return nil
+	content, err := ioutil.ReadAll(io.LimitReader(body, limit))
+	if err != nil {
+		return "", err
+	}
+
+	return string(content), nil
 }
 
 // GetFile downloads a given URL into a destination file.
 // If the flag overwrite is set to false, the destination file will not be overwritten and nothing will be downloaded.
+// The download is written to a partial file next to the destination file first, which is only renamed to the destination
+// file once the download completed. If the download fails with a transient error, it is retried with an exponential backoff
+// and resumed from where it stopped, as long as the server supports range requests. A partial file that is left behind by a
+// previous run is resumed as well.
 func GetFile(url, destinationFile string, overwrite bool) (bool, error) {
+	return GetFileWithProgress(url, destinationFile, overwrite, nil)
+}
+
+// GetFileWithProgress is a function that downloads a given URL into a destination file, like GetFile does.
+// While downloading, the amount of bytes that were written to the destination file so far is reported to a given function.
+func GetFileWithProgress(url, destinationFile string, overwrite bool, progress func(done int64)) (bool, error) {
 	if fileutil.PathExists(destinationFile) && !overwrite {
 		return false, nil
 	}
 
 	fileutil.TryRemove(destinationFile)
 
-	response, err := Client.Get(url) //nolint:gosec
-	if err != nil {
+	directory, _ := filepath.Split(destinationFile)
+	if err := os.MkdirAll(directory, 0755); err != nil {
 		return true, err
 	}
-	if response.StatusCode != 200 {
-		return true, fmt.Errorf("unexpected status while retrieving release file: %s", response.Status)
+
+	partialFile := destinationFile + PartialSuffix
+	backoff := RetryBackoff
+
+	for attempt := 0; ; attempt++ {
+		err := downloadPartial(url, partialFile, progress)
+		if err == nil {
+			return true, os.Rename(partialFile, destinationFile)
+		}
+		if attempt >= Retries || !isTransient(err) {
+			return true, err
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func downloadPartial(url, partialFile string, progress func(done int64)) error {
+	var offset int64
+	if fileInfo, err := os.Stat(partialFile); err == nil {
+		offset = fileInfo.Size()
+	}
+
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	if offset > 0 {
+		request.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	response, body, err := doWithStallTimeout(request)
+	if err != nil {
+		return err
 	}
 
 	defer func() {
 		_ = response.Body.Close()
 	}()
 
-	directory, _ := filepath.Split(destinationFile)
-	if err := os.MkdirAll(directory, 0755); err != nil {
-		return true, err
+	flags := os.O_CREATE | os.O_WRONLY
+	switch {
+	case response.StatusCode == http.StatusPartialContent && offset > 0:
+		// A server that ignores or misapplies the requested range would corrupt the partial file, so the download has to
+		// start from scratch instead.
+		if start, ok := contentRangeStart(response.Header.Get("Content-Range")); !ok || start != offset {
+			fileutil.TryRemove(partialFile)
+			return errRangeMismatch
+		}
+		flags |= os.O_APPEND
+	case response.StatusCode == http.StatusOK:
+		flags |= os.O_TRUNC
+		offset = 0
+	case response.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		// The partial file does not fit the remote file anymore, so the download has to start from scratch.
+		fileutil.TryRemove(partialFile)
+		return statusError{response.StatusCode, response.Status}
+	default:
+		return statusError{response.StatusCode, response.Status}
 	}
 
-	file, err := os.Create(destinationFile)
+	file, err := os.OpenFile(partialFile, flags, 0644) //nolint:gosec
 	if err != nil {
-		return true, err
+		return err
 	}
 
-	defer func() {
+	var writer io.Writer = file
+	if progress != nil {
+		progress(offset)
+		writer = &progressWriter{writer: file, done: offset, progress: progress}
+	}
+
+	if _, err := io.Copy(writer, body); err != nil {
 		_ = file.Close()
-	}()
+		return err
+	}
 
-	if _, err := io.Copy(file, response.Body); err != nil {
-		return true, err
+	return file.Close()
+}
+
+// contentRangeStart is a function that returns the first byte position of a Content-Range header like
+// "bytes 5000-9999/10000". If the header cannot be parsed, false is returned.
+func contentRangeStart(contentRange string) (int64, bool) {
+	byteRange := strings.TrimPrefix(contentRange, "bytes ")
+	separator := strings.Index(byteRange, "-")
+	if byteRange == contentRange || separator < 0 {
+		return 0, false
+	}
+
+	start, err := strconv.ParseInt(byteRange[:separator], 10, 64)
+	return start, err == nil
+}
+
+type progressWriter struct {
+	writer   io.Writer
+	done     int64
+	progress func(done int64)
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	w.done += int64(n)
+	w.progress(w.done)
+	return n, err
+}
+
+// doWithStallTimeout is a function that performs an HTTP request, which is aborted if no data is received for the duration
+// of Timeout. The returned reader has to be used to read the response body, since reading from it postpones the timeout.
+func doWithStallTimeout(request *http.Request) (*http.Response, io.Reader, error) {
+	ctx, cancel := context.WithCancel(request.Context())
+	stalled := new(int32)
+	timer := time.AfterFunc(Timeout, func() {
+		atomic.StoreInt32(stalled, 1)
+		cancel()
+	})
+
+	response, err := Client.Do(request.WithContext(ctx)) //nolint:bodyclose
+	if err != nil {
+		timer.Stop()
+		cancel()
+
+		if atomic.LoadInt32(stalled) == 1 {
+			return nil, nil, errStalled
+		}
+		return nil, nil, err
+	}
+
+	response.Body = &cancelingBody{ReadCloser: response.Body, cancel: cancel, timer: timer}
+	return response, &stallReader{reader: response.Body, timer: timer, stalled: stalled}, nil
+}
+
+type cancelingBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+	timer  *time.Timer
+}
+
+func (b *cancelingBody) Close() error {
+	b.timer.Stop()
+	b.cancel()
+	return b.ReadCloser.Close()
+}
+
+type stallReader struct {
+	reader  io.Reader
+	timer   *time.Timer
+	stalled *int32
+}
+
+func (r *stallReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if err != nil && atomic.LoadInt32(r.stalled) == 1 {
+		return n, errStalled
+	}
+
+	r.timer.Reset(Timeout)
+	return n, err
+}
+
+type statusError struct {
+	code   int
+	status string
+}
+
+func (e statusError) Error() string {
+	return fmt.Sprintf("unexpected status while retrieving release file: %s", e.status)
+}
+
+func isTransient(err error) bool {
+	var status statusError
+	if errors.As(err, &status) {
+		return status.code >= 500 || status.code == http.StatusRequestTimeout ||
+			status.code == http.StatusTooManyRequests || status.code == http.StatusRequestedRangeNotSatisfiable
+	}
+
+	// The HTTP client wraps every error in an url.Error, which itself claims to be a net.Error. Hence, only the wrapped error
+	// is inspected, to tell network failures apart from other kinds of errors.
+	var urlErr *neturl.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+
+	// A host that does not exist will not appear by waiting for it.
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return false
 	}
 
-	return true, nil
+	var netErr net.Error
+	return errors.Is(err, errStalled) || errors.Is(err, errRangeMismatch) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.As(err, &netErr)
 }
 
 // StaticResponseClient is a function that create a HTTP client that always produces the same response.
@@ -1,15 +1,22 @@
 package httputil
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetJSON(t *testing.T) {
@@ -109,3 +116,254 @@ func getNoPermissionDirectory(fileName string) string {
 
 	return filepath.Join("/", fileName)
 }
+
+func TestGetJSONIfModified(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` || r.Header.Get("If-Modified-Since") == "yesterday" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "yesterday")
+		_, _ = w.Write([]byte(`{"key": "value"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	var document map[string]string
+
+	validators, modified, err := GetJSONIfModified(server.URL, Validators{}, &document)
+	assert.NoError(t, err)
+	assert.True(t, modified)
+	assert.Equal(t, Validators{ETag: `"v1"`, LastModified: "yesterday"}, validators)
+	assert.Equal(t, map[string]string{"key": "value"}, document)
+
+	document = nil
+
+	validators, modified, err = GetJSONIfModified(server.URL, Validators{ETag: `"v1"`}, &document)
+	assert.NoError(t, err)
+	assert.False(t, modified)
+	assert.Equal(t, Validators{ETag: `"v1"`}, validators)
+	assert.Nil(t, document)
+
+	validators, modified, err = GetJSONIfModified(server.URL, Validators{LastModified: "yesterday"}, &document)
+	assert.NoError(t, err)
+	assert.False(t, modified)
+	assert.Equal(t, Validators{LastModified: "yesterday"}, validators)
+	assert.Nil(t, document)
+
+	validators, modified, err = GetJSONIfModified(server.URL, Validators{ETag: `"v0"`}, &document)
+	assert.NoError(t, err)
+	assert.True(t, modified)
+	assert.Equal(t, `"v1"`, validators.ETag)
+	assert.NotNil(t, document)
+}
+
+func TestGetText(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/document.txt" {
+			http.NotFound(w, r)
+			return
+		}
+
+		_, _ = w.Write([]byte("some text document"))
+	}))
+	t.Cleanup(server.Close)
+
+	text, err := GetText(server.URL+"/document.txt", 1024)
+	assert.NoError(t, err)
+	assert.Equal(t, "some text document", text)
+
+	text, err = GetText(server.URL+"/document.txt", 4)
+	assert.NoError(t, err)
+	assert.Equal(t, "some", text)
+
+	text, err = GetText(server.URL+"/missing.txt", 1024)
+	assert.Error(t, err)
+	assert.Empty(t, text)
+
+	_, err = GetText("::invalid", 1024)
+	assert.Error(t, err)
+}
+
+func TestGetFile_ResumesInterruptedDownload(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 1000)
+	requests := int32(0)
+	var ranges []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+
+		switch atomic.AddInt32(&requests, 1) {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+			_, _ = w.Write(content[:len(content)/2])
+			panic(http.ErrAbortHandler)
+		default:
+			http.ServeContent(w, r, "file", time.Time{}, bytes.NewReader(content))
+		}
+	}))
+	t.Cleanup(server.Close)
+	setRetryBackoff(t, time.Millisecond)
+
+	destinationFile := filepath.Join(t.TempDir(), "destination.txt")
+	var reported []int64
+
+	downloaded, err := GetFileWithProgress(server.URL, destinationFile, false, func(done int64) {
+		reported = append(reported, done)
+	})
+	assert.NoError(t, err)
+	assert.True(t, downloaded)
+	assert.Equal(t, []string{"", "", "bytes=5000-"}, ranges)
+	assert.Contains(t, reported, int64(5000))
+	assert.Equal(t, int64(len(content)), reported[len(reported)-1])
+	assert.NoFileExists(t, destinationFile+PartialSuffix)
+
+	downloadedContent, err := ioutil.ReadFile(destinationFile)
+	require.NoError(t, err)
+	assert.Equal(t, content, downloadedContent)
+}
+
+func TestGetFile_RestartsUnsatisfiableDownload(t *testing.T) {
+	content := []byte("content")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "file", time.Time{}, bytes.NewReader(content))
+	}))
+	t.Cleanup(server.Close)
+	setRetryBackoff(t, time.Millisecond)
+
+	destinationFile := filepath.Join(t.TempDir(), "destination.txt")
+	require.NoError(t, ioutil.WriteFile(destinationFile+PartialSuffix, []byte("stale partial content"), 0600))
+
+	downloaded, err := GetFile(server.URL, destinationFile, false)
+	assert.NoError(t, err)
+	assert.True(t, downloaded)
+
+	downloadedContent, err := ioutil.ReadFile(destinationFile)
+	require.NoError(t, err)
+	assert.Equal(t, content, downloadedContent)
+}
+
+func TestGetFile_RestartsMisappliedRange(t *testing.T) {
+	content := []byte("content")
+	var ranges []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		if r.Header.Get("Range") != "" {
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes 0-%d/%d", len(content)-1, len(content)))
+			w.WriteHeader(http.StatusPartialContent)
+			_, _ = w.Write(content)
+			return
+		}
+
+		_, _ = w.Write(content)
+	}))
+	t.Cleanup(server.Close)
+	setRetryBackoff(t, time.Millisecond)
+
+	destinationFile := filepath.Join(t.TempDir(), "destination.txt")
+	require.NoError(t, ioutil.WriteFile(destinationFile+PartialSuffix, []byte("con"), 0600))
+
+	downloaded, err := GetFile(server.URL, destinationFile, false)
+	assert.NoError(t, err)
+	assert.True(t, downloaded)
+	assert.Equal(t, []string{"bytes=3-", ""}, ranges)
+
+	downloadedContent, err := ioutil.ReadFile(destinationFile)
+	require.NoError(t, err)
+	assert.Equal(t, content, downloadedContent)
+}
+
+func Test_contentRangeStart(t *testing.T) {
+	start, ok := contentRangeStart("bytes 5000-9999/10000")
+	assert.True(t, ok)
+	assert.Equal(t, int64(5000), start)
+
+	start, ok = contentRangeStart("bytes 0-9/*")
+	assert.True(t, ok)
+	assert.Equal(t, int64(0), start)
+
+	_, ok = contentRangeStart("")
+	assert.False(t, ok)
+	_, ok = contentRangeStart("bytes */10000")
+	assert.False(t, ok)
+	_, ok = contentRangeStart("items 0-9/10")
+	assert.False(t, ok)
+}
+
+func TestGetFile_GivesUpAfterRetries(t *testing.T) {
+	requests := int32(0)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	t.Cleanup(server.Close)
+	setRetryBackoff(t, time.Millisecond)
+
+	destinationFile := filepath.Join(t.TempDir(), "destination.txt")
+
+	downloaded, err := GetFile(server.URL, destinationFile, false)
+	assert.Error(t, err)
+	assert.True(t, downloaded)
+	assert.Equal(t, int32(Retries+1), atomic.LoadInt32(&requests))
+	assert.NoFileExists(t, destinationFile)
+
+	atomic.StoreInt32(&requests, 0)
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	downloaded, err = GetFile(server.URL, destinationFile, false)
+	assert.Error(t, err)
+	assert.True(t, downloaded)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
+
+func TestGetFile_AbortsStalledDownload(t *testing.T) {
+	release := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "10")
+		_, _ = w.Write([]byte("01234"))
+		w.(http.Flusher).Flush()
+
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() {
+		close(release)
+	})
+	setRetryBackoff(t, time.Millisecond)
+
+	timeout, retries := Timeout, Retries
+	t.Cleanup(func() {
+		Timeout, Retries = timeout, retries
+	})
+	Timeout, Retries = 50*time.Millisecond, 1
+
+	destinationFile := filepath.Join(t.TempDir(), "destination.txt")
+
+	downloaded, err := GetFile(server.URL, destinationFile, false)
+	assert.True(t, errors.Is(err, errStalled))
+	assert.True(t, downloaded)
+	assert.NoFileExists(t, destinationFile)
+	assert.FileExists(t, destinationFile+PartialSuffix)
+}
+
+func setRetryBackoff(t *testing.T, backoff time.Duration) {
+	previousBackoff := RetryBackoff
+	t.Cleanup(func() {
+		RetryBackoff = previousBackoff
+	})
+
+	RetryBackoff = backoff
+}
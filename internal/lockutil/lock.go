@@ -0,0 +1,88 @@
+package lockutil
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	retryInterval = 50 * time.Millisecond
+)
+
+var (
+	// ErrLocked is the error that is returned, if a lock is held by another process and could not be acquired in time.
+	ErrLocked = errors.New("lock is held by another process")
+)
+
+// Lock is a struct that represents an advisory lock on a file, which is used to coordinate multiple processes.
+// The lock is reentrant within a process, so that it may be acquired multiple times, as long as it is released as often.
+type Lock struct {
+	path   string
+	mutex  sync.Mutex
+	count  int
+	handle lockHandle
+}
+
+// New is a constructor for the Lock struct. The given path is used as the lock file, which is created if necessary.
+func New(path string) *Lock {
+	return &Lock{path: path}
+}
+
+// Acquire is a function that acquires the lock, waiting for another process to release it if necessary.
+// If the lock cannot be acquired before the timeout elapses, ErrLocked is returned.
+func (l *Lock) Acquire(timeout time.Duration) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.count > 0 {
+		l.count++
+		return nil
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		handle, err := tryLock(l.path)
+		if err == nil {
+			l.handle = handle
+			l.count = 1
+			return nil
+		}
+		if !errors.Is(err, ErrLocked) || time.Now().After(deadline) {
+			return err
+		}
+
+		time.Sleep(retryInterval)
+	}
+}
+
+// Held is a function that checks if the lock is currently acquired by this process.
+func (l *Lock) Held() bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.count > 0
+}
+
+// Release is a function that releases the lock, once it was released as often as it was acquired.
+func (l *Lock) Release() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.count == 0 {
+		return errors.New("lock is not held")
+	}
+
+	l.count--
+	if l.count > 0 {
+		return nil
+	}
+
+	handle := l.handle
+	l.handle = nil
+	return handle.unlock()
+}
+
+type lockHandle interface {
+	unlock() error
+}
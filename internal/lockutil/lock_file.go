@@ -0,0 +1,72 @@
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+package lockutil
+
+import (
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type lockFileHandle struct {
+	path string
+}
+
+func tryLock(path string) (lockHandle, error) {
+	// Without flock, the existence of the lock file is the lock. It holds the id of the owning process, so that a lock file
+	// that was left behind by a process that was killed can be detected and removed.
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644) //nolint:gosec
+	if os.IsExist(err) {
+		if !isStale(path) {
+			return nil, ErrLocked
+		}
+
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		return nil, ErrLocked
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := file.WriteString(strconv.Itoa(os.Getpid())); err != nil {
+		_ = file.Close()
+		_ = os.Remove(path)
+		return nil, err
+	}
+	if err := file.Close(); err != nil {
+		_ = os.Remove(path)
+		return nil, err
+	}
+
+	return &lockFileHandle{path: path}, nil
+}
+
+func (h *lockFileHandle) unlock() error {
+	return os.Remove(h.path)
+}
+
+func isStale(path string) bool {
+	content, err := ioutil.ReadFile(path) //nolint:gosec
+	if err != nil {
+		return false
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	if err != nil {
+		// The lock file might have been created, but its owner did not write its id yet.
+		return false
+	}
+
+	// Looking up a process only fails on Windows, if it does not exist anymore. Elsewhere, a lock file is never considered
+	// stale.
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return true
+	}
+
+	_ = process.Release()
+	return false
+}
@@ -0,0 +1,41 @@
+// +build darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package lockutil
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+type flockHandle struct {
+	file *os.File
+}
+
+func tryLock(path string) (lockHandle, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+
+	// The lock is bound to the open file and is released by the kernel, even if the process is killed.
+	if err := unix.Flock(int(file.Fd()), unix.LOCK_EX|unix.LOCK_NB); err != nil {
+		_ = file.Close()
+
+		if err == unix.EWOULDBLOCK {
+			return nil, ErrLocked
+		}
+		return nil, err
+	}
+
+	return &flockHandle{file: file}, nil
+}
+
+func (h *flockHandle) unlock() error {
+	if err := unix.Flock(int(h.file.Fd()), unix.LOCK_UN); err != nil {
+		_ = h.file.Close()
+		return err
+	}
+
+	return h.file.Close()
+}
@@ -0,0 +1,61 @@
+package lockutil
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLock(t *testing.T) {
+	lockFile := filepath.Join(t.TempDir(), ".lock")
+	sut := New(lockFile)
+	other := New(lockFile)
+
+	assert.Error(t, sut.Release())
+
+	assert.False(t, sut.Held())
+	require.NoError(t, sut.Acquire(time.Second))
+	assert.FileExists(t, lockFile)
+	assert.True(t, sut.Held())
+	assert.False(t, other.Held())
+
+	err := other.Acquire(100 * time.Millisecond)
+	assert.True(t, errors.Is(err, ErrLocked))
+
+	require.NoError(t, sut.Acquire(0))
+	require.NoError(t, sut.Release())
+
+	err = other.Acquire(0)
+	assert.True(t, errors.Is(err, ErrLocked))
+
+	require.NoError(t, sut.Release())
+	assert.False(t, sut.Held())
+	assert.NoError(t, other.Acquire(0))
+	assert.NoError(t, other.Release())
+}
+
+func TestLock_WaitsForRelease(t *testing.T) {
+	lockFile := filepath.Join(t.TempDir(), ".lock")
+	sut := New(lockFile)
+	other := New(lockFile)
+
+	require.NoError(t, other.Acquire(time.Second))
+	time.AfterFunc(100*time.Millisecond, func() {
+		_ = other.Release()
+	})
+
+	assert.NoError(t, sut.Acquire(5*time.Second))
+	assert.NoError(t, sut.Release())
+}
+
+func TestLock_WithMissingDirectory(t *testing.T) {
+	sut := New(filepath.Join(t.TempDir(), "missing", ".lock"))
+
+	err := sut.Acquire(time.Second)
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrLocked))
+}
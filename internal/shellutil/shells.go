@@ -0,0 +1,159 @@
+package shellutil
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// The Shell type is a string that names a shell, for which scripts can be rendered.
+type Shell string
+
+const (
+	// Bash is the GNU Bourne-Again shell.
+	Bash = Shell("bash")
+	// Zsh is the Z shell.
+	Zsh = Shell("zsh")
+	// Fish is the friendly interactive shell.
+	Fish = Shell("fish")
+	// PowerShell is the Windows PowerShell as well as the cross-platform PowerShell Core.
+	PowerShell = Shell("powershell")
+)
+
+var (
+	// Shells is the list of all supported shells.
+	Shells = []Shell{Bash, Zsh, Fish, PowerShell}
+)
+
+// ParseShell is a function that returns the supported shell with a given name.
+func ParseShell(name string) (Shell, error) {
+	for _, shell := range Shells {
+		if string(shell) == strings.ToLower(name) {
+			return shell, nil
+		}
+	}
+
+	return "", fmt.Errorf("unsupported shell: %s", name)
+}
+
+// DetectShell is a function that guesses the shell the current process was started from.
+// On Windows, PowerShell is assumed. Everywhere else the SHELL environment variable is consulted, falling back to Bash if it
+// names an unsupported shell.
+func DetectShell() Shell {
+	if runtime.GOOS == "windows" {
+		return PowerShell
+	}
+
+	shell, err := ParseShell(filepath.Base(os.Getenv("SHELL")))
+	if err != nil {
+		return Bash
+	}
+
+	return shell
+}
+
+// SetVariable is a function that renders a statement that exports an environment variable.
+func (s Shell) SetVariable(name, value string) string {
+	switch s {
+	case Fish:
+		return fmt.Sprintf("set -gx %s %s", name, s.Quote(value))
+	case PowerShell:
+		return fmt.Sprintf("$env:%s = %s", name, s.Quote(value))
+	default:
+		return fmt.Sprintf("export %s=%s", name, s.Quote(value))
+	}
+}
+
+// PrependPath is a function that renders a statement that puts the given directories in front of the PATH.
+func (s Shell) PrependPath(directories ...string) string {
+	quoted := make([]string, len(directories))
+	for index, directory := range directories {
+		quoted[index] = s.Quote(directory)
+	}
+
+	switch s {
+	case Fish:
+		return fmt.Sprintf("set -gx PATH %s $PATH", strings.Join(quoted, " "))
+	case PowerShell:
+		separator := " + [IO.Path]::PathSeparator + "
+		return fmt.Sprintf("$env:PATH = %s%s$env:PATH", strings.Join(quoted, separator), separator)
+	default:
+		return fmt.Sprintf("export PATH=%s:\"$PATH\"", strings.Join(quoted, ":"))
+	}
+}
+
+// DirectoryHook is a function that renders a hook, that updates GOROOT whenever the working directory changes.
+// The new GOROOT is printed by the given command. If the command fails, the fallback directory is used instead. The bin
+// directory of the previous GOROOT is replaced by the bin directory of the new GOROOT in the PATH.
+func (s Shell) DirectoryHook(command []string, fallback string) string {
+	quoted := make([]string, len(command))
+	for index, argument := range command {
+		quoted[index] = s.Quote(argument)
+	}
+
+	commandLine := strings.Join(quoted, " ")
+	fallback = s.Quote(fallback)
+
+	switch s {
+	case Fish:
+		return strings.Join([]string{
+			"function _gmn_hook --on-variable PWD",
+			fmt.Sprintf("    set -l goroot (%s 2>/dev/null); or set goroot %s", commandLine, fallback),
+			"    test \"$goroot\" = \"$GOROOT\"; and return",
+			"    if set -l index (contains -i -- \"$GOROOT/bin\" $PATH)",
+			"        set -e PATH[$index]",
+			"    end",
+			"    set -gx GOROOT $goroot",
+			"    set -gx PATH \"$goroot/bin\" $PATH",
+			"end",
+			"_gmn_hook",
+		}, "\n")
+	case PowerShell:
+		return strings.Join([]string{
+			"$global:GmnPrompt = $function:prompt",
+			"function global:prompt {",
+			fmt.Sprintf("    $goroot = & %s 2>$null", commandLine),
+			fmt.Sprintf("    if ($LASTEXITCODE -ne 0) { $goroot = %s }", fallback),
+			"    if ($goroot -ne $env:GOROOT) {",
+			"        $paths = $env:PATH -split [IO.Path]::PathSeparator | Where-Object { $_ -ne (Join-Path \"$env:GOROOT\" 'bin') }",
+			"        $env:GOROOT = $goroot",
+			"        $env:PATH = (@(Join-Path $goroot 'bin') + $paths) -join [IO.Path]::PathSeparator",
+			"    }",
+			"    & $global:GmnPrompt",
+			"}",
+		}, "\n")
+	default:
+		hook := []string{
+			"_gmn_hook() {",
+			"    local goroot",
+			fmt.Sprintf("    goroot=\"$(%s 2>/dev/null)\" || goroot=%s", commandLine, fallback),
+			"    [ \"$goroot\" = \"$GOROOT\" ] && return",
+			"    PATH=\":$PATH:\"",
+			"    PATH=\"${PATH//\":$GOROOT/bin:\"/:}\"",
+			"    PATH=\"${PATH#:}\"",
+			"    export GOROOT=\"$goroot\" PATH=\"$goroot/bin:${PATH%:}\"",
+			"}",
+		}
+		if s == Zsh {
+			hook = append(hook, "autoload -Uz add-zsh-hook", "add-zsh-hook chpwd _gmn_hook")
+		} else {
+			hook = append(hook, "case \";${PROMPT_COMMAND};\" in *\";_gmn_hook;\"*) ;; "+
+				"*) PROMPT_COMMAND=\"_gmn_hook${PROMPT_COMMAND:+;$PROMPT_COMMAND}\";; esac")
+		}
+		return strings.Join(append(hook, "_gmn_hook"), "\n")
+	}
+}
+
+// Quote is a function that quotes a value, so that it is interpreted literally by the shell.
+func (s Shell) Quote(value string) string {
+	switch s {
+	case Fish:
+		return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+	case PowerShell:
+		return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+	default:
+		return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+	}
+}
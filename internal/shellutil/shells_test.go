@@ -0,0 +1,79 @@
+package shellutil
+
+import (
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseShell(t *testing.T) {
+	for _, expected := range Shells {
+		shell, err := ParseShell(string(expected))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, shell)
+	}
+
+	shell, err := ParseShell("PowerShell")
+	assert.NoError(t, err)
+	assert.Equal(t, PowerShell, shell)
+
+	shell, err = ParseShell("tcsh")
+	assert.Error(t, err)
+	assert.Empty(t, shell)
+}
+
+func TestDetectShell(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		assert.Equal(t, PowerShell, DetectShell())
+		return
+	}
+
+	originalShell := os.Getenv("SHELL")
+	t.Cleanup(func() {
+		_ = os.Setenv("SHELL", originalShell)
+	})
+
+	require.NoError(t, os.Setenv("SHELL", "/usr/bin/zsh"))
+	assert.Equal(t, Zsh, DetectShell())
+
+	require.NoError(t, os.Setenv("SHELL", "/bin/tcsh"))
+	assert.Equal(t, Bash, DetectShell())
+}
+
+func TestShell_SetVariable(t *testing.T) {
+	assert.Equal(t, "export GOROOT='/root/.gmn/go-default'", Bash.SetVariable("GOROOT", "/root/.gmn/go-default"))
+	assert.Equal(t, "export GOROOT='/root/.gmn/go-default'", Zsh.SetVariable("GOROOT", "/root/.gmn/go-default"))
+	assert.Equal(t, "set -gx GOROOT '/root/.gmn/go-default'", Fish.SetVariable("GOROOT", "/root/.gmn/go-default"))
+	assert.Equal(t, "$env:GOROOT = 'C:\\gmn\\go-default'", PowerShell.SetVariable("GOROOT", "C:\\gmn\\go-default"))
+}
+
+func TestShell_PrependPath(t *testing.T) {
+	assert.Equal(t, `export PATH='/a':'/b':"$PATH"`, Bash.PrependPath("/a", "/b"))
+	assert.Equal(t, `set -gx PATH '/a' '/b' $PATH`, Fish.PrependPath("/a", "/b"))
+	assert.Equal(
+		t,
+		`$env:PATH = 'C:\a' + [IO.Path]::PathSeparator + $env:PATH`,
+		PowerShell.PrependPath(`C:\a`),
+	)
+}
+
+func TestShell_DirectoryHook(t *testing.T) {
+	for _, shell := range Shells {
+		hook := shell.DirectoryHook([]string{"/usr/bin/gmn", "current", "-path"}, "/root/.gmn/go-default")
+		assert.Contains(t, hook, shell.Quote("/usr/bin/gmn"))
+		assert.Contains(t, hook, shell.Quote("/root/.gmn/go-default"))
+		assert.Contains(t, hook, "GOROOT")
+	}
+
+	assert.Contains(t, Zsh.DirectoryHook([]string{"gmn"}, "/"), "add-zsh-hook chpwd _gmn_hook")
+	assert.Contains(t, Bash.DirectoryHook([]string{"gmn"}, "/"), "PROMPT_COMMAND")
+}
+
+func TestShell_Quote(t *testing.T) {
+	assert.Equal(t, `'it'\''s'`, Bash.Quote("it's"))
+	assert.Equal(t, `'it\'s \\'`, Fish.Quote(`it's \`))
+	assert.Equal(t, `'it''s'`, PowerShell.Quote("it's"))
+}
@@ -0,0 +1,212 @@
+package manager
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+
+	"github.com/jangraefen/go-man/pkg/releases"
+)
+
+const (
+	archiveDirectoryName = "archives"
+
+	// The suffix of the file next to a cached archive, that records that it was verified against an independent checksum.
+	verifiedSuffix = ".verified"
+)
+
+var (
+	archiveVersionPattern = regexp.MustCompile(`^go([0-9]+(\.[0-9]+)*((alpha|beta|rc)[0-9]+)?)\.`)
+)
+
+// CachedArchive is a struct that describes a downloaded archive, that is kept in the cache directory.
+type CachedArchive struct {
+	// The path of the archive file.
+	Path string
+	// The sha256 checksum of the archive, as it was announced by the release list.
+	Sha256 string
+	// The size in bytes of the archive file.
+	Size int64
+	// The version of the Go SDK that is contained in the archive. Might be nil, if it cannot be derived from the file name.
+	Version *version.Version
+}
+
+// CachedArchives is a function that lists all archives that are currently kept in the cache directory.
+func (m *GoManager) CachedArchives() ([]CachedArchive, error) {
+	checksumInfos, err := ioutil.ReadDir(m.archiveCacheDirectory())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var archives []CachedArchive
+
+	for _, checksumInfo := range checksumInfos {
+		if !checksumInfo.IsDir() {
+			continue
+		}
+
+		checksumDirectory := filepath.Join(m.archiveCacheDirectory(), checksumInfo.Name())
+		fileInfos, err := ioutil.ReadDir(checksumDirectory)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, fileInfo := range fileInfos {
+			if strings.HasSuffix(fileInfo.Name(), verifiedSuffix) || fileInfo.Name() == lockFileName {
+				continue
+			}
+
+			archives = append(archives, CachedArchive{
+				Path:    filepath.Join(checksumDirectory, fileInfo.Name()),
+				Sha256:  checksumInfo.Name(),
+				Size:    fileInfo.Size(),
+				Version: archiveVersion(fileInfo.Name()),
+			})
+		}
+	}
+
+	sort.Slice(archives, func(i, j int) bool {
+		return archives[i].Path < archives[j].Path
+	})
+
+	return archives, nil
+}
+
+// PruneCache is a function that removes all cached archives of Go SDK versions that are currently not installed.
+// Since only the installations of this root directory are known, a cache directory outside of it is never pruned, as it
+// might be shared with other root directories that still use the archives.
+// Feedback is reported to the task of the manager, while failures are returned as errors.
+func (m *GoManager) PruneCache() error {
+	if m.isSharedCache() {
+		return fmt.Errorf("cache directory %s might be shared with other roots and cannot be pruned", m.cacheDirectory())
+	}
+
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	m.task.Printf("Pruning cached archives of versions that are not installed")
+	pruneTask := m.task.Step()
+
+	archives, err := m.CachedArchives()
+	if err != nil {
+		return err
+	}
+
+	for _, archive := range archives {
+		if archive.Version != nil && m.isInstalledForAnyPlatform(archive.Version) {
+			continue
+		}
+
+		removeDescription := "Removing " + filepath.Base(archive.Path)
+		removeFunction := func() error { return os.RemoveAll(filepath.Dir(archive.Path)) }
+		if err := pruneTask.Track(removeDescription, removeFunction); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ClearCache is a function that removes all cached archives and release lists.
+// Only the files that are owned by gmn are removed, since the cache directory might contain unrelated files as well.
+// Feedback is reported to the task of the manager, while failures are returned as errors.
+func (m *GoManager) ClearCache() error {
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	m.task.Printf("Clearing cache")
+	clearTask := m.task.Step()
+
+	archivesDescription := "Deleting cached archives"
+	archivesFunction := func() error { return os.RemoveAll(m.archiveCacheDirectory()) }
+	if err := clearTask.Track(archivesDescription, archivesFunction); err != nil {
+		return err
+	}
+
+	releaseListsDescription := "Deleting cached release lists"
+	releaseListsFunction := func() error {
+		releaseLists, err := filepath.Glob(filepath.Join(m.cacheDirectory(), releases.CacheFilePattern))
+		if err != nil {
+			return err
+		}
+
+		for _, releaseList := range releaseLists {
+			if err := os.Remove(releaseList); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+	return clearTask.Track(releaseListsDescription, releaseListsFunction)
+}
+
+func (m *GoManager) cacheDirectory() string {
+	if m.CacheDirectory == "" {
+		return filepath.Join(m.RootDirectory, cacheDirectoryName)
+	}
+
+	return m.CacheDirectory
+}
+
+// isSharedCache is a function that checks if the cache directory is located outside of the root directory.
+func (m *GoManager) isSharedCache() bool {
+	return filepath.Clean(m.cacheDirectory()) != filepath.Join(m.RootDirectory, cacheDirectoryName)
+}
+
+func (m *GoManager) archiveCacheDirectory() string {
+	return filepath.Join(m.cacheDirectory(), archiveDirectoryName)
+}
+
+func (m *GoManager) cachedArchivePath(file releases.ReleaseFile) string {
+	return filepath.Join(m.archiveCacheDirectory(), file.Sha256, file.Filename)
+}
+
+func (m *GoManager) isInstalled(versionNumber *version.Version) bool {
+	for _, installedVersion := range m.InstalledVersions {
+		if installedVersion.Equal(versionNumber) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *GoManager) isInstalledForAnyPlatform(versionNumber *version.Version) bool {
+	for _, installation := range m.ForeignInstallations {
+		if installation.Version.Equal(versionNumber) {
+			return true
+		}
+	}
+
+	return m.isInstalled(versionNumber)
+}
+
+func archiveVersion(fileName string) *version.Version {
+	match := archiveVersionPattern.FindStringSubmatch(fileName)
+	if match == nil {
+		return nil
+	}
+
+	versionNumber, err := version.NewVersion(match[1])
+	if err != nil {
+		return nil
+	}
+
+	return versionNumber
+}
@@ -0,0 +1,203 @@
+package manager
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jangraefen/go-man/pkg/releases"
+	"github.com/jangraefen/go-man/pkg/tasks"
+)
+
+func TestGoManager_CachedArchives(t *testing.T) {
+	tempDir := t.TempDir()
+	sut := &GoManager{
+		RootDirectory:     tempDir,
+		InstalledVersions: version.Collection{},
+		SelectedVersion:   nil,
+		task: &tasks.Task{
+			Output: os.Stdout,
+			Error:  os.Stderr,
+		},
+	}
+
+	archives, err := sut.CachedArchives()
+	assert.NoError(t, err)
+	assert.Empty(t, archives)
+
+	setupCachedArchive(t, sut, "1111", "go1.15.2.linux-amd64.tar.gz")
+	setupCachedArchive(t, sut, "2222", "go1.16rc1.windows-amd64.zip")
+	setupCachedArchive(t, sut, "3333", "unknown.zip")
+	require.NoError(t, ioutil.WriteFile(filepath.Join(sut.archiveCacheDirectory(), "stray.file"), nil, 0600))
+	verifiedRecord := filepath.Join(sut.archiveCacheDirectory(), "1111", "go1.15.2.linux-amd64.tar.gz"+verifiedSuffix)
+	require.NoError(t, ioutil.WriteFile(verifiedRecord, nil, 0600))
+
+	archives, err = sut.CachedArchives()
+	assert.NoError(t, err)
+	require.Len(t, archives, 3)
+	assert.Equal(t, "1111", archives[0].Sha256)
+	assert.Equal(t, int64(len("go1.15.2.linux-amd64.tar.gz")), archives[0].Size)
+	assert.Equal(t, version.Must(version.NewVersion("1.15.2")), archives[0].Version)
+	assert.Equal(t, version.Must(version.NewVersion("1.16rc1")), archives[1].Version)
+	assert.Nil(t, archives[2].Version)
+}
+
+func TestGoManager_PruneCache(t *testing.T) {
+	installedVersion := version.Must(version.NewVersion("1.15.2"))
+
+	tempDir := t.TempDir()
+	setupInstallation(t, tempDir, true, "1.15.2")
+
+	sut := &GoManager{
+		RootDirectory:     tempDir,
+		InstalledVersions: version.Collection{installedVersion},
+		SelectedVersion:   nil,
+		task: &tasks.Task{
+			Output: os.Stdout,
+			Error:  os.Stderr,
+		},
+	}
+
+	assert.NoError(t, sut.PruneCache())
+
+	installedArchive := setupCachedArchive(t, sut, "1111", "go1.15.2.linux-amd64.tar.gz")
+	uninstalledArchive := setupCachedArchive(t, sut, "2222", "go1.14.9.linux-amd64.tar.gz")
+	unknownArchive := setupCachedArchive(t, sut, "3333", "unknown.zip")
+
+	assert.NoError(t, sut.PruneCache())
+	assert.FileExists(t, installedArchive)
+	assert.NoFileExists(t, uninstalledArchive)
+	assert.NoFileExists(t, unknownArchive)
+
+	sut.CacheDirectory = filepath.Join(t.TempDir(), "shared-cache")
+	sharedArchive := setupCachedArchive(t, sut, "2222", "go1.14.9.linux-amd64.tar.gz")
+
+	assert.Error(t, sut.PruneCache())
+	assert.FileExists(t, sharedArchive)
+}
+
+func TestGoManager_ClearCache(t *testing.T) {
+	sut := &GoManager{
+		RootDirectory:     t.TempDir(),
+		InstalledVersions: version.Collection{version.Must(version.NewVersion("1.15.2"))},
+		SelectedVersion:   nil,
+		task: &tasks.Task{
+			Output: os.Stdout,
+			Error:  os.Stderr,
+		},
+	}
+
+	assert.NoError(t, sut.ClearCache())
+
+	setupCachedArchive(t, sut, "1111", "go1.15.2.linux-amd64.tar.gz")
+	releaseList := filepath.Join(sut.cacheDirectory(), "releases-all-0123456789ab.json")
+	require.NoError(t, ioutil.WriteFile(releaseList, []byte("{}"), 0600))
+	unrelatedFile := filepath.Join(sut.cacheDirectory(), "unrelated.txt")
+	require.NoError(t, ioutil.WriteFile(unrelatedFile, nil, 0600))
+
+	assert.NoError(t, sut.ClearCache())
+	assert.NoDirExists(t, sut.archiveCacheDirectory())
+	assert.NoFileExists(t, releaseList)
+	assert.FileExists(t, unrelatedFile)
+}
+
+func TestGoManager_Install_WithCachedArchive(t *testing.T) {
+	validVersion := version.Must(version.NewVersion("1.15.2"))
+	tempDir := t.TempDir()
+
+	sut, err := NewManager(&tasks.Task{
+		Output: os.Stdout,
+		Error:  os.Stderr,
+	}, tempDir)
+	require.NoError(t, err)
+
+	file := setupCachedRelease(t, sut, validVersion)
+
+	assert.NoError(t, sut.Install(validVersion, runtime.GOOS, runtime.GOARCH, releases.IncludeAll))
+	assert.DirExists(t, filepath.Join(tempDir, "go1.15.2"))
+	assert.FileExists(t, sut.cachedArchivePath(file))
+	assert.Contains(t, sut.InstalledVersions, validVersion)
+
+	assert.NoError(t, sut.Uninstall(validVersion))
+	assert.NoError(t, sut.Install(validVersion, runtime.GOOS, runtime.GOARCH, releases.IncludeAll))
+	assert.DirExists(t, filepath.Join(tempDir, "go1.15.2"))
+}
+
+func setupCachedArchive(t *testing.T, manager *GoManager, checksum, fileName string) string {
+	t.Helper()
+
+	archivePath := filepath.Join(manager.archiveCacheDirectory(), checksum, fileName)
+	require.NoError(t, os.MkdirAll(filepath.Dir(archivePath), 0700))
+	require.NoError(t, ioutil.WriteFile(archivePath, []byte(fileName), 0600))
+
+	return archivePath
+}
+
+// setupCachedRelease is a function that places a minimal distribution of a given version into the cache of a manager and
+// announces it in the cached release list, so that it can be installed without any network access.
+func setupCachedRelease(t *testing.T, manager *GoManager, versionNumber *version.Version) releases.ReleaseFile {
+	t.Helper()
+
+	versionName := fmt.Sprintf("go%s", toVersionName(versionNumber))
+	archivePath := filepath.Join(t.TempDir(), "archive.zip")
+	writeDistribution(t, archivePath, versionName)
+
+	content, err := ioutil.ReadFile(archivePath)
+	require.NoError(t, err)
+
+	file := releases.ReleaseFile{
+		Filename: fmt.Sprintf("%s.%s-%s.zip", versionName, runtime.GOOS, runtime.GOARCH),
+		OS:       runtime.GOOS,
+		Arch:     runtime.GOARCH,
+		Version:  versionName,
+		Sha256:   fmt.Sprintf("%x", sha256.Sum256(content)),
+		Size:     int32(len(content)),
+		Kind:     releases.ArchiveFile,
+	}
+
+	cachedPath := manager.cachedArchivePath(file)
+	require.NoError(t, os.MkdirAll(filepath.Dir(cachedPath), 0700))
+	require.NoError(t, ioutil.WriteFile(cachedPath, content, 0600))
+	require.NoError(t, ioutil.WriteFile(cachedPath+verifiedSuffix, []byte(file.Sha256), 0600))
+
+	releases.ReleaseListCache[releases.IncludeAll] = append(
+		releases.ReleaseListCache[releases.IncludeAll],
+		&releases.Release{Version: versionName, Stable: true, Files: []releases.ReleaseFile{file}},
+	)
+	t.Cleanup(func() {
+		delete(releases.ReleaseListCache, releases.IncludeAll)
+	})
+
+	return file
+}
+
+func writeDistribution(t *testing.T, archivePath, versionName string) {
+	t.Helper()
+
+	archiveFile, err := os.Create(archivePath)
+	require.NoError(t, err)
+
+	archiveWriter := zip.NewWriter(archiveFile)
+
+	versionWriter, err := archiveWriter.Create("go/VERSION")
+	require.NoError(t, err)
+	_, err = versionWriter.Write([]byte(versionName))
+	require.NoError(t, err)
+
+	toolWriter, err := archiveWriter.Create("go/bin/go")
+	require.NoError(t, err)
+	_, err = toolWriter.Write([]byte("tool"))
+	require.NoError(t, err)
+
+	require.NoError(t, archiveWriter.Close())
+	require.NoError(t, archiveFile.Close())
+}
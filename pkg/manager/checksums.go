@@ -0,0 +1,97 @@
+package manager
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jangraefen/go-man/pkg/releases"
+)
+
+const (
+	// PinnedChecksumsFileName is the name of the file, that pins the checksums of release files for a project.
+	PinnedChecksumsFileName = ".gmn.sum"
+)
+
+// PinnedChecksums is a struct that holds the checksums of release files, that were pinned in a checksum file.
+// The checksum file has the same format as the output of the sha256sum tool, so each line consists of a checksum and a file
+// name. Empty lines and lines starting with a '#' are ignored.
+type PinnedChecksums struct {
+	// The path of the checksum file that the checksums were read from.
+	Path string
+	// The pinned checksums, indexed by the name of the release file.
+	Checksums map[string]string
+}
+
+// FindPinnedChecksums is a function that searches for a checksum file, that pins the checksums of release files.
+// Starting at the given directory, each parent directory is searched for a .gmn.sum file and the first one found is read.
+// If no checksum file exists at all, nil is returned.
+func FindPinnedChecksums(workingDirectory string) (*PinnedChecksums, error) {
+	directory, err := filepath.Abs(workingDirectory)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		candidate := filepath.Join(directory, PinnedChecksumsFileName)
+		if _, err := os.Stat(candidate); err == nil {
+			return ReadPinnedChecksums(candidate)
+		}
+
+		parent := filepath.Dir(directory)
+		if parent == directory {
+			return nil, nil
+		}
+
+		directory = parent
+	}
+}
+
+// ReadPinnedChecksums is a function that reads the pinned checksums from a given checksum file.
+func ReadPinnedChecksums(fileName string) (*PinnedChecksums, error) {
+	file, err := os.Open(fileName) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	pinned := &PinnedChecksums{Path: fileName, Checksums: map[string]string{}}
+
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 || !releases.IsChecksum(fields[0]) {
+			return nil, fmt.Errorf("%s:%d: expected a sha256 checksum followed by a file name", fileName, lineNumber)
+		}
+
+		// The sha256sum tool marks files that were read in binary mode with a leading asterisk.
+		pinned.Checksums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+	}
+
+	return pinned, scanner.Err()
+}
+
+// Verify is a function that checks if a release file has the checksum, that is pinned for it.
+// Release files without a pinned checksum are accepted, so that a checksum file only needs to list the files it cares about.
+func (p *PinnedChecksums) Verify(file releases.ReleaseFile, checksum string) error {
+	if p == nil {
+		return nil
+	}
+
+	pinnedChecksum, pinned := p.Checksums[file.Filename]
+	if pinned && pinnedChecksum != checksum {
+		return NewError(ErrChecksumMismatch, "checksum of %s does not match the checksum pinned in %s", file.Filename, p.Path)
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package manager
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jangraefen/go-man/pkg/releases"
+)
+
+const (
+	helloChecksum = "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
+)
+
+func TestFindPinnedChecksums(t *testing.T) {
+	tempDir := t.TempDir()
+	projectDirectory := filepath.Join(tempDir, "project", "cmd")
+	require.NoError(t, os.MkdirAll(projectDirectory, 0700))
+
+	pinned, err := FindPinnedChecksums(projectDirectory)
+	assert.NoError(t, err)
+	assert.Nil(t, pinned)
+
+	checksumFile := filepath.Join(tempDir, "project", PinnedChecksumsFileName)
+	require.NoError(t, ioutil.WriteFile(checksumFile, []byte(helloChecksum+"  go1.15.2.linux-amd64.tar.gz\n"), 0600))
+
+	pinned, err = FindPinnedChecksums(projectDirectory)
+	require.NoError(t, err)
+	assert.Equal(t, checksumFile, pinned.Path)
+	assert.Equal(t, map[string]string{"go1.15.2.linux-amd64.tar.gz": helloChecksum}, pinned.Checksums)
+}
+
+func TestReadPinnedChecksums(t *testing.T) {
+	checksumFile := filepath.Join(t.TempDir(), PinnedChecksumsFileName)
+	content := "# Pinned checksums\n\n" +
+		"B94D27B9934D3E08A52E52D7DA7DABFAC484EFE37A5380EE9088F7ACE2EFCDE9  go1.15.2.linux-amd64.tar.gz\n" +
+		helloChecksum + " *go1.15.2.windows-amd64.zip\n"
+	require.NoError(t, ioutil.WriteFile(checksumFile, []byte(content), 0600))
+
+	pinned, err := ReadPinnedChecksums(checksumFile)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"go1.15.2.linux-amd64.tar.gz": helloChecksum,
+		"go1.15.2.windows-amd64.zip":  helloChecksum,
+	}, pinned.Checksums)
+
+	require.NoError(t, ioutil.WriteFile(checksumFile, []byte("\nnot-a-checksum  go1.15.2.linux-amd64.tar.gz\n"), 0600))
+	_, err = ReadPinnedChecksums(checksumFile)
+	assert.EqualError(t, err, checksumFile+":2: expected a sha256 checksum followed by a file name")
+
+	_, err = ReadPinnedChecksums(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
+
+func TestPinnedChecksums_Verify(t *testing.T) {
+	file := releases.ReleaseFile{Filename: "go1.15.2.linux-amd64.tar.gz"}
+	otherFile := releases.ReleaseFile{Filename: "go1.15.2.windows-amd64.zip"}
+
+	var sut *PinnedChecksums
+	assert.NoError(t, sut.Verify(file, helloChecksum))
+
+	sut = &PinnedChecksums{Path: ".gmn.sum", Checksums: map[string]string{file.Filename: helloChecksum}}
+	assert.NoError(t, sut.Verify(file, helloChecksum))
+	assert.NoError(t, sut.Verify(otherFile, "other"))
+
+	err := sut.Verify(file, "other")
+	assert.EqualError(t, err, "checksum of go1.15.2.linux-amd64.tar.gz does not match the checksum pinned in .gmn.sum")
+	assert.True(t, errors.Is(err, ErrChecksumMismatch))
+}
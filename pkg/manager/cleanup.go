@@ -7,8 +7,14 @@ import (
 )
 
 // Cleanup is a function that removes all Go SDK installations that are currently not considered stable.
-// Feedback is directly printed to the stdout or stderr, so nothing is returned here.
+// Feedback is reported to the task of the manager, while failures are returned as errors.
 func (m *GoManager) Cleanup() error {
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	m.task.Printf("Removing all non-stable versions")
 
 	versionsToRemove, err := filterNonStableVersions(m.InstalledVersions)
@@ -22,6 +28,23 @@ func (m *GoManager) Cleanup() error {
 		}
 	}
 
+	foreignInstallations := make([]Installation, len(m.ForeignInstallations))
+	copy(foreignInstallations, m.ForeignInstallations)
+
+	for _, installation := range foreignInstallations {
+		stable, err := isStableVersion(installation.Version)
+		if err != nil {
+			return err
+		}
+		if stable {
+			continue
+		}
+
+		if err := m.UninstallForPlatform(installation.Version, installation.OS, installation.Arch); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -29,14 +52,19 @@ func filterNonStableVersions(versions version.Collection) (version.Collection, e
 	filtered := version.Collection{}
 
 	for _, v := range versions {
-		_, exists, err := releases.GetForVersion(releases.IncludeStable, v)
+		stable, err := isStableVersion(v)
 		if err != nil {
 			return nil, err
 		}
-		if !exists {
+		if !stable {
 			filtered = append(filtered, v)
 		}
 	}
 
 	return filtered, nil
 }
+
+func isStableVersion(versionNumber *version.Version) (bool, error) {
+	_, exists, err := releases.GetForVersion(releases.IncludeStable, versionNumber)
+	return exists, err
+}
@@ -33,9 +33,8 @@ func TestGoManager_Cleanup(t *testing.T) {
 		InstalledVersions: version.Collection{stableVersion, unstableVersion},
 		SelectedVersion:   nil,
 		task: &tasks.Task{
-			ErrorExitCode: 1,
-			Output:        os.Stdout,
-			Error:         os.Stderr,
+			Output: os.Stdout,
+			Error:  os.Stderr,
 		},
 	}
 
@@ -46,22 +45,26 @@ func TestGoManager_Cleanup(t *testing.T) {
 	assert.NoError(t, sut.Cleanup())
 }
 
-func TestGoManager_Cleanup_WithInvalid(t *testing.T) {
+func TestGoManager_Cleanup_WithStaleState(t *testing.T) {
 	unstableVersion := version.Must(version.NewVersion("1.11.0"))
 
 	tempDir := t.TempDir()
+	setupInstallation(t, tempDir, false, "1.11.0")
+
 	sut := &GoManager{
 		RootDirectory:     tempDir,
 		InstalledVersions: version.Collection{unstableVersion},
 		SelectedVersion:   nil,
 		task: &tasks.Task{
-			ErrorExitCode: 1,
-			Output:        os.Stdout,
-			Error:         os.Stderr,
+			Output: os.Stdout,
+			Error:  os.Stderr,
 		},
 	}
 
-	assert.Error(t, sut.Cleanup())
+	// The installation was removed by another process, so it must not be uninstalled again. Broken installations are kept.
+	assert.NoError(t, sut.Cleanup())
+	assert.Empty(t, sut.InstalledVersions)
+	assert.DirExists(t, filepath.Join(tempDir, "go1.11.0"))
 }
 
 func TestGoManager_Cleanup_WithHTTPError(t *testing.T) {
@@ -72,14 +75,15 @@ func TestGoManager_Cleanup_WithHTTPError(t *testing.T) {
 	unstableVersion := version.Must(version.NewVersion("1.11.0"))
 
 	tempDir := t.TempDir()
+	setupInstallation(t, tempDir, true, "1.11.0")
+
 	sut := &GoManager{
 		RootDirectory:     tempDir,
 		InstalledVersions: version.Collection{unstableVersion},
 		SelectedVersion:   nil,
 		task: &tasks.Task{
-			ErrorExitCode: 1,
-			Output:        os.Stdout,
-			Error:         os.Stderr,
+			Output: os.Stdout,
+			Error:  os.Stderr,
 		},
 	}
 
@@ -0,0 +1,73 @@
+package manager
+
+import (
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// sdkCommand is a function that creates a command, that runs inside the environment of a given Go SDK installation.
+// The environment of the current process is inherited, but GOROOT points to the installation and its bin directory is put in
+// front of the PATH. Commands without a path are looked up in the bin directory of the installation first.
+func sdkCommand(sdkDirectory, name string, args []string) *exec.Cmd {
+	binDirectory := filepath.Join(sdkDirectory, "bin")
+
+	if !strings.ContainsRune(name, filepath.Separator) && !strings.ContainsRune(name, '/') {
+		if toolPath, err := exec.LookPath(filepath.Join(binDirectory, name)); err == nil {
+			name = toolPath
+		}
+	}
+
+	command := exec.Command(name, args...) //nolint:gosec
+	command.Env = sdkEnvironment(sdkDirectory, os.Environ())
+	command.Stdin = os.Stdin
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+
+	return command
+}
+
+func sdkEnvironment(sdkDirectory string, environment []string) []string {
+	binDirectory := filepath.Join(sdkDirectory, "bin")
+	result := make([]string, 0, len(environment)+2)
+	path := binDirectory
+
+	for _, variable := range environment {
+		key := strings.SplitN(variable, "=", 2)[0]
+
+		switch {
+		case isVariable(key, "GOROOT"):
+			continue
+		case isVariable(key, "PATH"):
+			if value := strings.TrimPrefix(variable, key+"="); value != "" {
+				path += string(os.PathListSeparator) + value
+			}
+			continue
+		}
+
+		result = append(result, variable)
+	}
+
+	return append(result, "GOROOT="+sdkDirectory, "PATH="+path)
+}
+
+func isVariable(key, name string) bool {
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(key, name)
+	}
+
+	return key == name
+}
+
+func runCommand(command *exec.Cmd) error {
+	// Interrupts are delivered to the whole process group, so the command receives them anyway. They are only kept from
+	// terminating this process, so that the exit status of the command can be reported correctly.
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	defer signal.Stop(signals)
+
+	return command.Run()
+}
@@ -0,0 +1,34 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSdkEnvironment(t *testing.T) {
+	sdkDirectory := filepath.Join("root", "go1.15.2")
+	binDirectory := filepath.Join(sdkDirectory, "bin")
+
+	environment := sdkEnvironment(sdkDirectory, []string{"GOROOT=/stale", "PATH=/usr/bin", "HOME=/home/gopher"})
+	assert.Len(t, environment, 3)
+	assert.Contains(t, environment, "HOME=/home/gopher")
+	assert.Contains(t, environment, "GOROOT="+sdkDirectory)
+	assert.Contains(t, environment, "PATH="+binDirectory+string(os.PathListSeparator)+"/usr/bin")
+
+	environment = sdkEnvironment(sdkDirectory, []string{})
+	assert.Len(t, environment, 2)
+	assert.Contains(t, environment, "GOROOT="+sdkDirectory)
+	assert.Contains(t, environment, "PATH="+binDirectory)
+}
+
+func TestSdkCommand(t *testing.T) {
+	sdkDirectory := t.TempDir()
+
+	command := sdkCommand(sdkDirectory, "I_DO_NOT_EXIST", []string{"arg"})
+	assert.Equal(t, []string{"I_DO_NOT_EXIST", "arg"}, command.Args)
+	assert.Contains(t, command.Env, "GOROOT="+sdkDirectory)
+	assert.Error(t, runCommand(command))
+}
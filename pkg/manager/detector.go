@@ -1,13 +1,24 @@
 package manager
 
 import (
+	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"regexp"
+	"runtime"
 	"strings"
 
 	"github.com/hashicorp/go-version"
 )
 
+const (
+	develPrefix = "devel "
+)
+
+var (
+	develVersionPattern = regexp.MustCompile(`^devel go(\d+\.\d+)-([0-9a-f]+)`)
+)
+
 func detectGoVersion(sdkDirectory string) (*version.Version, error) {
 	versionPath := filepath.Join(sdkDirectory, "VERSION")
 
@@ -16,5 +27,54 @@ func detectGoVersion(sdkDirectory string) (*version.Version, error) {
 		return nil, err
 	}
 
-	return version.NewVersion(strings.TrimPrefix(string(versionContent), "go"))
+	// Starting with Go 1.21, the VERSION file carries additional lines with build metadata after the version itself.
+	versionLine := strings.TrimSpace(strings.SplitN(string(versionContent), "\n", 2)[0])
+
+	// Development versions are named after the upcoming release and the commit they were built from, like
+	// "devel go1.22-0123456789ab". The commit is kept as part of the pre-release, so that each commit has a distinct version.
+	if strings.HasPrefix(versionLine, develPrefix) {
+		matches := develVersionPattern.FindStringSubmatch(versionLine)
+		if matches == nil {
+			return nil, fmt.Errorf("unsupported development version %q", versionLine)
+		}
+
+		return version.NewVersion(fmt.Sprintf("%s-devel.%s", matches[1], matches[2]))
+	}
+
+	return version.NewVersion(strings.TrimPrefix(versionLine, "go"))
+}
+
+// detectGoPlatform is a function that determines the platform, that an installation of the Go SDK was built for.
+// The platform is taken from the name of the installation directory, if it is qualified by one. Otherwise, the tool
+// directory of the installation is inspected. Installations without tools are assumed to be built for the current platform.
+func detectGoPlatform(sdkDirectory string, versionNumber *version.Version) (string, string) {
+	qualifiedPrefix := fmt.Sprintf("go%s.", toVersionName(versionNumber))
+	if directoryName := filepath.Base(sdkDirectory); strings.HasPrefix(directoryName, qualifiedPrefix) {
+		if platform := strings.SplitN(strings.TrimPrefix(directoryName, qualifiedPrefix), "-", 2); len(platform) == 2 {
+			return platform[0], platform[1]
+		}
+	}
+
+	fileInfos, err := ioutil.ReadDir(filepath.Join(sdkDirectory, "pkg", "tool"))
+	if err != nil {
+		return runtime.GOOS, runtime.GOARCH
+	}
+
+	var platforms [][]string
+	for _, fileInfo := range fileInfos {
+		if platform := strings.SplitN(fileInfo.Name(), "_", 2); fileInfo.IsDir() && len(platform) == 2 {
+			// Tools for other platforms may be built next to the native ones, so the native platform takes precedence.
+			if isNativePlatform(platform[0], platform[1]) {
+				return platform[0], platform[1]
+			}
+
+			platforms = append(platforms, platform)
+		}
+	}
+
+	if len(platforms) == 0 {
+		return runtime.GOOS, runtime.GOARCH
+	}
+
+	return platforms[0][0], platforms[0][1]
 }
@@ -1,11 +1,15 @@
 package manager
 
 import (
+	"io/ioutil"
+	"os"
 	"path/filepath"
 	"runtime"
 	"testing"
 
+	"github.com/hashicorp/go-version"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDetectGoVersion(t *testing.T) {
@@ -24,3 +28,59 @@ func TestDetectGoVersion(t *testing.T) {
 	assert.Error(t, err)
 	assert.Nil(t, goVersion)
 }
+
+func TestDetectGoVersion_WithMultiLineVersionFile(t *testing.T) {
+	sdkDirectory := t.TempDir()
+	require.NoError(t, ioutil.WriteFile(
+		filepath.Join(sdkDirectory, "VERSION"),
+		[]byte("go1.21.3\ntime 2023-10-09T17:04:35Z\n"),
+		0600,
+	))
+
+	goVersion, err := detectGoVersion(sdkDirectory)
+	assert.NoError(t, err)
+	assert.Equal(t, version.Must(version.NewVersion("1.21.3")), goVersion)
+}
+
+func TestDetectGoVersion_WithDevelopmentVersion(t *testing.T) {
+	sdkDirectory := t.TempDir()
+	versionFile := filepath.Join(sdkDirectory, "VERSION")
+
+	require.NoError(t, ioutil.WriteFile(versionFile, []byte("devel go1.22-0123456789ab"), 0600))
+	goVersion, err := detectGoVersion(sdkDirectory)
+	assert.NoError(t, err)
+	assert.Equal(t, version.Must(version.NewVersion("1.22-devel.0123456789ab")), goVersion)
+	assert.Equal(t, "1.22devel.0123456789ab", toVersionName(goVersion))
+
+	require.NoError(t, ioutil.WriteFile(versionFile, []byte("devel +0123456789ab Tue Oct 3 2023"), 0600))
+	goVersion, err = detectGoVersion(sdkDirectory)
+	assert.Error(t, err)
+	assert.Nil(t, goVersion)
+}
+
+func TestDetectGoPlatform(t *testing.T) {
+	versionNumber := version.Must(version.NewVersion("1.15.2"))
+	rootDirectory := t.TempDir()
+
+	operatingSystem, arch := detectGoPlatform(filepath.Join(rootDirectory, "go1.15.2"), versionNumber)
+	assert.Equal(t, runtime.GOOS, operatingSystem)
+	assert.Equal(t, runtime.GOARCH, arch)
+
+	operatingSystem, arch = detectGoPlatform(filepath.Join(rootDirectory, "go1.15.2.plan9-arm"), versionNumber)
+	assert.Equal(t, "plan9", operatingSystem)
+	assert.Equal(t, "arm", arch)
+
+	sdkDirectory := filepath.Join(rootDirectory, "go1.15.2")
+	require.NoError(t, os.MkdirAll(filepath.Join(sdkDirectory, "pkg", "tool", "plan9_arm"), 0700))
+
+	operatingSystem, arch = detectGoPlatform(sdkDirectory, versionNumber)
+	assert.Equal(t, "plan9", operatingSystem)
+	assert.Equal(t, "arm", arch)
+
+	nativeToolDirectory := filepath.Join(sdkDirectory, "pkg", "tool", runtime.GOOS+"_"+runtime.GOARCH)
+	require.NoError(t, os.MkdirAll(nativeToolDirectory, 0700))
+
+	operatingSystem, arch = detectGoPlatform(sdkDirectory, versionNumber)
+	assert.Equal(t, runtime.GOOS, operatingSystem)
+	assert.Equal(t, runtime.GOARCH, arch)
+}
@@ -0,0 +1,210 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/jangraefen/go-man/pkg/releases"
+)
+
+// Diagnostic is a struct that holds the outcome of a single check, that is performed by Diagnose.
+type Diagnostic struct {
+	// A short description of what was checked.
+	Description string
+	// The problem that was found by the check, or nil if there was none.
+	Problem error
+	// A hint on how to solve the problem. Only set, if a problem was found.
+	Hint string
+}
+
+// Diagnose is a function that checks the health of the installations and of the environment that they are used in.
+// The environment is given as a list of "key=value" strings, like os.Environ returns it. The release source is queried to
+// check whether the releases can be retrieved. All checks are performed, regardless of any problems found on the way.
+func (m *GoManager) Diagnose(environment []string, source releases.ReleaseSource) []Diagnostic {
+	path := lookupVariable(environment, "PATH")
+
+	return []Diagnostic{
+		m.diagnoseSelection(),
+		m.diagnoseInstallations(),
+		m.diagnosePath(path),
+		m.diagnoseShadowing(path),
+		m.diagnoseGoRoot(lookupVariable(environment, "GOROOT")),
+		diagnoseReleaseSource(source),
+	}
+}
+
+func (m *GoManager) diagnoseSelection() Diagnostic {
+	diagnostic := Diagnostic{Description: "Checking the selected installation"}
+
+	if _, err := os.Lstat(m.SelectedDirectory()); os.IsNotExist(err) {
+		diagnostic.Problem = errors.New("no version is selected")
+		diagnostic.Hint = "Select a version with: gmn select <version>"
+		return diagnostic
+	}
+
+	if _, err := os.Stat(m.SelectedDirectory()); err != nil {
+		diagnostic.Problem = fmt.Errorf("%s is a dangling link", m.SelectedDirectory())
+		diagnostic.Hint = "Select an installed version again with: gmn select <version>"
+		return diagnostic
+	}
+
+	if _, err := detectGoVersion(m.SelectedDirectory()); err != nil {
+		diagnostic.Problem = fmt.Errorf("%s does not contain a Go installation: %w", m.SelectedDirectory(), err)
+		diagnostic.Hint = "Select an installed version again with: gmn select <version>"
+	}
+
+	return diagnostic
+}
+
+func (m *GoManager) diagnoseInstallations() Diagnostic {
+	diagnostic := Diagnostic{Description: "Checking the installed versions"}
+
+	fileInfos, err := ioutil.ReadDir(m.RootDirectory)
+	if err != nil {
+		diagnostic.Problem = err
+		return diagnostic
+	}
+
+	var mismatches []string
+	for _, fileInfo := range fileInfos {
+		if !fileInfo.IsDir() || !strings.HasPrefix(fileInfo.Name(), "go") || fileInfo.Name() == selectedDirectoryName {
+			continue
+		}
+
+		sdkDirectory := filepath.Join(m.RootDirectory, fileInfo.Name())
+		detectedVersion, err := detectGoVersion(sdkDirectory)
+		if err != nil {
+			continue
+		}
+
+		operatingSystem, arch := detectGoPlatform(sdkDirectory, detectedVersion)
+		expectedName := filepath.Base(m.SDKDirectoryForPlatform(detectedVersion, operatingSystem, arch))
+		if expectedName != fileInfo.Name() {
+			mismatches = append(mismatches, fmt.Sprintf("%s contains %s", fileInfo.Name(), expectedName))
+		}
+	}
+
+	if len(mismatches) > 0 {
+		diagnostic.Problem = fmt.Errorf("the VERSION files do not match the directories: %s", strings.Join(mismatches, ", "))
+		diagnostic.Hint = "Uninstall and install the affected versions again"
+	}
+
+	return diagnostic
+}
+
+func (m *GoManager) diagnosePath(path string) Diagnostic {
+	diagnostic := Diagnostic{Description: "Checking the PATH"}
+
+	binDirectory := filepath.Join(m.SelectedDirectory(), "bin")
+	for _, directory := range filepath.SplitList(path) {
+		if sameDirectory(directory, binDirectory) || sameDirectory(directory, m.ShimDirectory()) {
+			return diagnostic
+		}
+	}
+
+	diagnostic.Problem = fmt.Errorf("neither %s nor %s is part of the PATH", binDirectory, m.ShimDirectory())
+	diagnostic.Hint = `Configure your shell with: eval "$(gmn env)"`
+	return diagnostic
+}
+
+func (m *GoManager) diagnoseShadowing(path string) Diagnostic {
+	diagnostic := Diagnostic{Description: "Checking which go is used"}
+
+	goExecutable, found := lookPath(executableName("go"), path)
+	if !found {
+		return diagnostic
+	}
+
+	directory := filepath.Dir(goExecutable)
+	if sameDirectory(directory, filepath.Join(m.SelectedDirectory(), "bin")) || sameDirectory(directory, m.ShimDirectory()) {
+		return diagnostic
+	}
+
+	if resolvedExecutable, err := filepath.EvalSymlinks(goExecutable); err == nil &&
+		strings.HasPrefix(resolvedExecutable, filepath.Clean(m.RootDirectory)+string(filepath.Separator)) {
+		return diagnostic
+	}
+
+	diagnostic.Problem = fmt.Errorf("%s comes first on the PATH and shadows the selected installation", goExecutable)
+	diagnostic.Hint = fmt.Sprintf("Move %s in front of %s on the PATH", filepath.Join(m.SelectedDirectory(), "bin"), directory)
+	return diagnostic
+}
+
+func (m *GoManager) diagnoseGoRoot(goRoot string) Diagnostic {
+	diagnostic := Diagnostic{Description: "Checking the GOROOT"}
+
+	if goRoot == "" || sameDirectory(goRoot, m.SelectedDirectory()) {
+		return diagnostic
+	}
+
+	if _, err := detectGoVersion(goRoot); err != nil {
+		diagnostic.Problem = fmt.Errorf("GOROOT is set to %s, which does not contain a Go installation", goRoot)
+		diagnostic.Hint = `Unset GOROOT or configure your shell with: eval "$(gmn env)"`
+		return diagnostic
+	}
+
+	resolvedRoot, err := filepath.EvalSymlinks(goRoot)
+	if err == nil && strings.HasPrefix(resolvedRoot, filepath.Clean(m.RootDirectory)+string(filepath.Separator)) {
+		return diagnostic
+	}
+
+	diagnostic.Problem = fmt.Errorf("GOROOT is set to %s, which is not managed by gmn", goRoot)
+	diagnostic.Hint = `Unset GOROOT or configure your shell with: eval "$(gmn env)"`
+	return diagnostic
+}
+
+func diagnoseReleaseSource(source releases.ReleaseSource) Diagnostic {
+	diagnostic := Diagnostic{Description: "Checking the release source"}
+
+	if _, err := source.ListReleases(releases.IncludeStable); err != nil {
+		diagnostic.Problem = fmt.Errorf("releases could not be retrieved: %w", err)
+		diagnostic.Hint = "Check your network connection or configure a mirror with GMNMIRROR"
+	}
+
+	return diagnostic
+}
+
+func lookupVariable(environment []string, name string) string {
+	var value string
+
+	for _, variable := range environment {
+		parts := strings.SplitN(variable, "=", 2)
+		if len(parts) == 2 && isVariable(parts[0], name) {
+			value = parts[1]
+		}
+	}
+
+	return value
+}
+
+func lookPath(executable, path string) (string, bool) {
+	for _, directory := range filepath.SplitList(path) {
+		if directory == "" {
+			continue
+		}
+
+		candidate := filepath.Join(directory, executable)
+		fileInfo, err := os.Stat(candidate)
+		if err != nil || fileInfo.IsDir() {
+			continue
+		}
+		if runtime.GOOS == "windows" || fileInfo.Mode()&0111 != 0 {
+			return candidate, true
+		}
+	}
+
+	return "", false
+}
+
+func sameDirectory(first, second string) bool {
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(filepath.Clean(first), filepath.Clean(second))
+	}
+
+	return filepath.Clean(first) == filepath.Clean(second)
+}
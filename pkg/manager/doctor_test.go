@@ -0,0 +1,104 @@
+package manager
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jangraefen/go-man/pkg/releases"
+	"github.com/jangraefen/go-man/pkg/tasks"
+)
+
+func TestGoManager_Diagnose(t *testing.T) {
+	selectedVersion := version.Must(version.NewVersion("1.15.2"))
+	tempDir := t.TempDir()
+	rootDirectory := filepath.Join(tempDir, "root")
+	require.NoError(t, os.MkdirAll(rootDirectory, 0700))
+
+	setupInstallation(t, rootDirectory, true, "1.15.2")
+
+	releaseFile := filepath.Join(tempDir, "releases.json")
+	require.NoError(t, ioutil.WriteFile(releaseFile, []byte(`[{"version": "go1.15.2", "stable": true}]`), 0600))
+	source := &releases.FileSource{Path: releaseFile}
+
+	sut := &GoManager{
+		RootDirectory:     rootDirectory,
+		InstalledVersions: version.Collection{selectedVersion},
+		task: &tasks.Task{
+			Output: os.Stdout,
+			Error:  os.Stderr,
+		},
+	}
+
+	binDirectory := filepath.Join(sut.SelectedDirectory(), "bin")
+	environment := []string{"PATH=" + binDirectory, "HOME=" + tempDir}
+
+	assert.Equal(t, []string{"Checking the selected installation"}, problems(sut.Diagnose(environment, source)))
+
+	// Installations for other platforms are expected in directories that are qualified by their platform.
+	foreignDirectory := filepath.Join(rootDirectory, "go1.15.2.plan9-arm")
+	require.NoError(t, os.MkdirAll(foreignDirectory, 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(foreignDirectory, "VERSION"), []byte("go1.15.2"), 0600))
+
+	require.NoError(t, sut.Select(selectedVersion))
+	assert.Empty(t, problems(sut.Diagnose(environment, source)))
+
+	// Another go executable that comes first on the PATH shadows the selected installation.
+	otherDirectory := filepath.Join(tempDir, "other")
+	setupInstallation(t, otherDirectory, true, "1.13.1")
+	otherBinDirectory := filepath.Join(otherDirectory, "go1.13.1", "bin")
+	require.NoError(t, os.MkdirAll(otherBinDirectory, 0700))
+	otherExecutable := filepath.Join(otherBinDirectory, executableName("go"))
+	require.NoError(t, ioutil.WriteFile(otherExecutable, []byte("go"), 0700)) //nolint:gosec
+
+	environment = []string{
+		"PATH=" + strings.Join([]string{otherBinDirectory, binDirectory}, string(os.PathListSeparator)),
+		"GOROOT=" + filepath.Join(otherDirectory, "go1.13.1"),
+	}
+	assert.Equal(
+		t,
+		[]string{"Checking which go is used", "Checking the GOROOT"},
+		problems(sut.Diagnose(environment, source)),
+	)
+
+	// A directory whose VERSION file does not match its name and an unreachable release source are reported as well.
+	mismatchedDirectory := filepath.Join(rootDirectory, "go1.14.9")
+	require.NoError(t, os.MkdirAll(mismatchedDirectory, 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(mismatchedDirectory, "VERSION"), []byte("go1.13.1"), 0600))
+
+	environment = []string{"PATH=" + tempDir, "GOROOT=" + filepath.Join(tempDir, "missing")}
+	assert.Equal(
+		t,
+		[]string{
+			"Checking the installed versions",
+			"Checking the PATH",
+			"Checking the GOROOT",
+			"Checking the release source",
+		},
+		problems(sut.Diagnose(environment, &releases.FileSource{Path: filepath.Join(tempDir, "missing.json")})),
+	)
+
+	// The selection directory points nowhere, once the selected installation is removed behind the back of gmn.
+	require.NoError(t, os.RemoveAll(sut.SDKDirectory(selectedVersion)))
+	diagnostics := sut.Diagnose([]string{"PATH=" + binDirectory}, source)
+	assert.Contains(t, diagnostics[0].Problem.Error(), "dangling")
+	assert.NotEmpty(t, diagnostics[0].Hint)
+}
+
+func problems(diagnostics []Diagnostic) []string {
+	var descriptions []string
+
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Problem != nil {
+			descriptions = append(descriptions, diagnostic.Description)
+		}
+	}
+
+	return descriptions
+}
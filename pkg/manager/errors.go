@@ -0,0 +1,72 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNotInstalled is the kind of error, that is returned if a version of the Go SDK is required but not installed.
+	ErrNotInstalled = errors.New("not installed")
+	// ErrAlreadyInstalled is the kind of error, that is returned if a version of the Go SDK is installed a second time.
+	ErrAlreadyInstalled = errors.New("already installed")
+	// ErrReleaseNotFound is the kind of error, that is returned if the release source does not offer a requested version.
+	ErrReleaseNotFound = errors.New("release not found")
+	// ErrPlatformUnavailable is the kind of error, that is returned if a version of the Go SDK is not available for, or cannot
+	// be used on a platform.
+	ErrPlatformUnavailable = errors.New("platform unavailable")
+	// ErrChecksumMismatch is the kind of error, that is returned if a file does not match the checksum it is expected to have.
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+
+	// errChecksumUnavailable is the kind of error, that is returned if the checksum file of an independent origin cannot be
+	// retrieved. Unlike a mismatch, it does not indicate that a file was tampered with.
+	errChecksumUnavailable = errors.New("checksum unavailable")
+
+	errorKinds = []error{ErrNotInstalled, ErrAlreadyInstalled, ErrReleaseNotFound, ErrPlatformUnavailable, ErrChecksumMismatch}
+)
+
+// Error is a struct for errors, that belong to one of the kinds of errors of this package, like ErrNotInstalled.
+// The kind can be checked with errors.Is, while the message of the error describes the specific cause.
+type Error struct {
+	// The kind of the error.
+	Kind error
+	// The specific cause of the error.
+	Err error
+}
+
+// NewError is a constructor for the Error struct, that formats the specific cause like fmt.Errorf does.
+func NewError(kind error, format string, args ...interface{}) *Error {
+	return &Error{Kind: kind, Err: fmt.Errorf(format, args...)}
+}
+
+// Error is a function that returns the message of the specific cause.
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap is a function that returns the specific cause, so that it can be inspected by errors.Is and errors.As as well.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is is a function that checks if the error belongs to the given kind of errors.
+func (e *Error) Is(target error) bool {
+	return e.Kind == target
+}
+
+// commonErrorKind is a function that returns the kind of errors, that all given errors belong to. If they belong to
+// different kinds or to none at all, nil is returned.
+func commonErrorKind(errs []error) error {
+	for _, kind := range errorKinds {
+		common := len(errs) > 0
+		for _, err := range errs {
+			common = common && errors.Is(err, kind)
+		}
+
+		if common {
+			return kind
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError(t *testing.T) {
+	sut := NewError(ErrNotInstalled, "version %s was not found", "1.15.2")
+
+	assert.EqualError(t, sut, "version 1.15.2 was not found")
+	assert.True(t, errors.Is(sut, ErrNotInstalled))
+	assert.False(t, errors.Is(sut, ErrAlreadyInstalled))
+
+	wrapped := fmt.Errorf("could not select: %w", sut)
+	assert.True(t, errors.Is(wrapped, ErrNotInstalled))
+
+	var managerError *Error
+	assert.True(t, errors.As(wrapped, &managerError))
+	assert.Equal(t, ErrNotInstalled, managerError.Kind)
+}
+
+func TestCommonErrorKind(t *testing.T) {
+	notInstalled := NewError(ErrNotInstalled, "version 1.15.2 was not found")
+	alreadyInstalled := NewError(ErrAlreadyInstalled, "installation skipped")
+
+	assert.Nil(t, commonErrorKind(nil))
+	assert.Nil(t, commonErrorKind([]error{errors.New("unrelated")}))
+	assert.Nil(t, commonErrorKind([]error{notInstalled, alreadyInstalled}))
+	assert.Nil(t, commonErrorKind([]error{alreadyInstalled, errors.New("unrelated")}))
+	assert.Equal(t, ErrAlreadyInstalled, commonErrorKind([]error{alreadyInstalled}))
+	assert.Equal(t, ErrAlreadyInstalled, commonErrorKind([]error{alreadyInstalled, alreadyInstalled}))
+}
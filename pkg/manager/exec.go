@@ -0,0 +1,26 @@
+package manager
+
+import (
+	"errors"
+
+	"github.com/hashicorp/go-version"
+
+	"github.com/jangraefen/go-man/internal/fileutil"
+)
+
+// Exec is a function that runs a command inside the environment of an existing installation of the Go SDK.
+// GOROOT points to the installation and its bin directory is put in front of the PATH, so the command is not affected by the
+// currently selected version, which also remains untouched. The standard streams of the current process are passed to the
+// command.
+func (m *GoManager) Exec(versionNumber *version.Version, command []string) error {
+	if len(command) == 0 {
+		return errors.New("no command given to execute")
+	}
+
+	versionDirectory := m.SDKDirectory(versionNumber)
+	if !fileutil.PathExists(versionDirectory) {
+		return NewError(ErrNotInstalled, "version %v was not found", toVersionName(versionNumber))
+	}
+
+	return runCommand(sdkCommand(versionDirectory, command[0], command[1:]))
+}
@@ -0,0 +1,48 @@
+package manager
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jangraefen/go-man/pkg/tasks"
+)
+
+func TestGoManager_Exec(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell commands are not available on windows")
+	}
+
+	validVersion := version.Must(version.NewVersion("1.15.2"))
+	invalidVersion := version.Must(version.NewVersion("42.1337.3"))
+
+	tempDir := t.TempDir()
+	setupInstallation(t, tempDir, true, validVersion.String())
+
+	sut := &GoManager{
+		RootDirectory:     tempDir,
+		InstalledVersions: version.Collection{validVersion},
+		SelectedVersion:   nil,
+		task: &tasks.Task{
+			Output: os.Stdout,
+			Error:  os.Stderr,
+		},
+	}
+
+	assert.Error(t, sut.Exec(validVersion, nil))
+	assert.Error(t, sut.Exec(invalidVersion, []string{"sh", "-c", "exit 0"}))
+
+	assert.NoError(t, sut.Exec(validVersion, []string{"sh", "-c", `[ "$GOROOT" = "` + sut.SDKDirectory(validVersion) + `" ]`}))
+
+	err := sut.Exec(validVersion, []string{"sh", "-c", "exit 3"})
+	var exitErr *exec.ExitError
+	require.True(t, errors.As(err, &exitErr))
+	assert.Equal(t, 3, exitErr.ExitCode())
+	assert.Nil(t, sut.SelectedVersion)
+}
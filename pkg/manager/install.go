@@ -1,9 +1,15 @@
 package manager
 
 import (
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"path/filepath"
 	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/hashicorp/go-version"
 
@@ -11,81 +17,345 @@ import (
 	"github.com/jangraefen/go-man/internal/fileutil"
 	"github.com/jangraefen/go-man/internal/httputil"
 	"github.com/jangraefen/go-man/pkg/releases"
+	"github.com/jangraefen/go-man/pkg/tasks"
 )
 
 // Install is a function that installs new instances of the Go SDK.
 // As installation parameters the version number, operating system and platform architecture are considered when choosing the
 // correct installation artifacts. The releaseType parameter is used to limit the amount of accepted versions. Feedback is
-// directly printed to the stdout or stderr, so nothing is returned here.
-//nolint:funlen
+// reported to the task of the manager, while failures are returned as errors.
 func (m *GoManager) Install(versionNumber *version.Version, operatingSystem, arch string, releaseType releases.ReleaseType) error {
-	m.task.Printf("Installing %s %s-%s:", versionNumber, operatingSystem, arch)
-	installTask := m.task.Step()
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := m.install(m.task, versionNumber, operatingSystem, arch, releaseType); err != nil {
+		return err
+	}
+
+	m.addInstallation(versionNumber, operatingSystem, arch)
+	return nil
+}
+
+// InstallAll is a function that installs multiple instances of the Go SDK concurrently, like Install does for a single one.
+// At most the given number of jobs are installing at the same time. If multiple jobs are installing, the output of each
+// installation is printed as complete lines, that are prefixed with the version being installed. A failing installation
+// never stops the others, regardless of the number of jobs, but all failures are reported in the returned error. If all
+// failures are of the same kind, like ErrAlreadyInstalled, the returned error is of that kind as well.
+func (m *GoManager) InstallAll(
+	versionNumbers version.Collection,
+	operatingSystem, arch string,
+	releaseType releases.ReleaseType,
+	jobs int,
+) error {
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	versionNumbers = uniqueVersions(versionNumbers)
+	if jobs > len(versionNumbers) {
+		jobs = len(versionNumbers)
+	}
+
+	if jobs < 1 {
+		jobs = 1
+	}
+
+	var failureMutex sync.Mutex
+	var failedVersions version.Collection
+	var failures []error
+
+	queue := make(chan *version.Version)
+	waitGroup := sync.WaitGroup{}
+	waitGroup.Add(jobs)
+
+	for i := 0; i < jobs; i++ {
+		go func() {
+			defer waitGroup.Done()
+
+			for versionNumber := range queue {
+				jobTask := m.task
+				if jobs > 1 {
+					jobTask = m.task.Concurrent(toVersionName(versionNumber))
+				}
+
+				if err := m.install(jobTask, versionNumber, operatingSystem, arch, releaseType); err != nil {
+					failureMutex.Lock()
+					failedVersions = append(failedVersions, versionNumber)
+					failures = append(failures, err)
+					failureMutex.Unlock()
+					continue
+				}
+
+				m.addInstallation(versionNumber, operatingSystem, arch)
+			}
+		}()
+	}
+
+	for _, versionNumber := range versionNumbers {
+		queue <- versionNumber
+	}
+	close(queue)
+	waitGroup.Wait()
+
+	return installationFailure(failedVersions, failures)
+}
+
+// installationFailure is a function that combines the errors of failed installations into a single error, that names the
+// versions they belong to. If all errors are of the same kind, the combined error is of that kind as well. If no
+// installation failed, nil is returned.
+func installationFailure(failedVersions version.Collection, failures []error) error {
+	if len(failures) == 0 {
+		return nil
+	}
+
+	messages := make([]string, 0, len(failures))
+	for index, failure := range failures {
+		messages = append(messages, fmt.Sprintf("%s: %s", failedVersions[index], failure))
+	}
+	sort.Strings(messages)
+
+	err := fmt.Errorf("installation failed for %s", strings.Join(messages, "; "))
+	if kind := commonErrorKind(failures); kind != nil {
+		return &Error{Kind: kind, Err: err}
+	}
+
+	return err
+}
+
+//nolint:funlen
+func (m *GoManager) install(
+	task *tasks.Task,
+	versionNumber *version.Version,
+	operatingSystem, arch string,
+	releaseType releases.ReleaseType,
+) error {
+	task.Printf("Installing %s %s-%s:", versionNumber, operatingSystem, arch)
+	installTask := task.Step()
 
 	release, releasePresent, err := releases.GetForVersion(releaseType, versionNumber)
 	if err != nil {
 		return err
 	}
 	if !releasePresent {
-		return fmt.Errorf("release with version %s not present", versionNumber)
+		return NewError(ErrReleaseNotFound, "release with version %s not present", versionNumber)
 	}
 
 	files := release.FindFiles(operatingSystem, arch, releases.ArchiveFile)
 	if len(files) != 1 {
-		return fmt.Errorf("release %s with %s-%s not present", versionNumber, operatingSystem, arch)
+		return NewError(ErrPlatformUnavailable, "release %s with %s-%s not present", versionNumber, operatingSystem, arch)
 	}
 
 	file := files[0]
-	downloadedArchive := filepath.Join(m.RootDirectory, file.Filename)
-	extractionDirectory := filepath.Join(m.RootDirectory, fmt.Sprintf("extracting-%s", file.Version))
-	sdkDirectory := filepath.Join(m.RootDirectory, file.Version)
+	downloadedArchive := m.cachedArchivePath(file)
+	sdkDirectory := m.SDKDirectoryForPlatform(versionNumber, operatingSystem, arch)
+	extractionDirectory := filepath.Join(m.RootDirectory, stagingDirectoryPrefix+filepath.Base(sdkDirectory))
 
 	if fileutil.PathExists(sdkDirectory) {
-		return fmt.Errorf("installation skipped, since %s is already present", sdkDirectory)
+		return NewError(ErrAlreadyInstalled, "installation skipped, since %s is already present", sdkDirectory)
 	}
 
-	defer fileutil.TryRemove(downloadedArchive)
+	// A staging directory that is left behind by an interrupted installation would prevent the extraction.
+	fileutil.TryRemove(extractionDirectory)
 	defer fileutil.TryRemove(extractionDirectory)
 
-	downloadDescription := "Downloading distribution"
-	downloadFunction := func() error { return downloadRelease(file, downloadedArchive) }
-	if err := installTask.Track(downloadDescription, downloadFunction); err != nil {
+	if err := m.fetchArchive(installTask, file); err != nil {
 		return err
 	}
 
-	checksumDescription := "Verifying download integrity"
-	checksumFunction := func() error { return verifyDownload(file, downloadedArchive) }
-	if err := installTask.Track(checksumDescription, checksumFunction); err != nil {
+	extractDescription := "Extracting distribution"
+	extractFunction := func(progress *tasks.Progress) error {
+		return extractRelease(downloadedArchive, extractionDirectory, progress.Report)
+	}
+	if err := installTask.TrackProgress(extractDescription, int64(file.Size), extractFunction); err != nil {
 		return err
 	}
 
-	extractDescription := "Extracting distribution"
-	extractFunction := func() error { return extractRelease(downloadedArchive, extractionDirectory) }
-	if err := installTask.Track(extractDescription, extractFunction); err != nil {
+	return m.publish(installTask, extractionDirectory, sdkDirectory, versionNumber, newManifest(release, file))
+}
+
+// fetchArchive is a function that provides a verified copy of a release file in the cache directory. While the archive is
+// verified, downloaded and moved into place, the lock on its directory is held.
+func (m *GoManager) fetchArchive(task *tasks.Task, file releases.ReleaseFile) error {
+	downloadedArchive := m.cachedArchivePath(file)
+
+	unlock, err := m.lockArchive(task, downloadedArchive)
+	if err != nil {
 		return err
 	}
+	defer unlock()
+
+	// A previously downloaded archive is reused, as long as it is still intact. Otherwise, it is downloaded again. Unless it
+	// is recorded that the archive was verified against the checksum file of an independent origin before, this is done now.
+	// Only an archive that does not match its checksum is dropped, while any other failure keeps it for the next attempt.
+	if fileutil.PathExists(downloadedArchive) {
+		independent := releases.ChecksumBaseURL != "" && !isVerifiedIndependently(downloadedArchive, file.Sha256)
+		err := m.trackVerification(task, "Verifying cached distribution", file, downloadedArchive, independent)
+		if err != nil && !errors.Is(err, ErrChecksumMismatch) {
+			return err
+		}
+		if err != nil {
+			fileutil.TryRemove(downloadedArchive)
+			fileutil.TryRemove(downloadedArchive + verifiedSuffix)
+		}
+	}
 
+	if !fileutil.PathExists(downloadedArchive) {
+		downloadDescription := "Downloading distribution"
+		downloadFunction := func(progress *tasks.Progress) error {
+			return downloadRelease(file, downloadedArchive, progress.Report)
+		}
+		if err := task.TrackProgress(downloadDescription, int64(file.Size), downloadFunction); err != nil {
+			return err
+		}
+
+		// A fresh download is also verified against the checksum file of an independent origin, so that a compromised release
+		// source cannot serve a tampered archive together with a matching checksum.
+		independent := releases.ChecksumBaseURL != ""
+		if err := m.trackVerification(task, "Verifying download integrity", file, downloadedArchive, independent); err != nil {
+			fileutil.TryRemove(downloadedArchive)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// trackVerification is a function that tracks the verification of a cached archive. If the checksum file of the independent
+// origin cannot be retrieved, a warning is reported and the archive is accepted based on the checksum of the release list,
+// so that mirrors without access to the independent origin remain usable.
+func (m *GoManager) trackVerification(
+	task *tasks.Task,
+	description string,
+	file releases.ReleaseFile,
+	downloadedArchive string,
+	independent bool,
+) error {
+	var unavailable error
+	verifyFunction := func() error {
+		err := m.verifyArchive(file, downloadedArchive, independent)
+		if errors.Is(err, errChecksumUnavailable) {
+			unavailable = err
+			return nil
+		}
+
+		return err
+	}
+	if err := task.Track(description, verifyFunction); err != nil {
+		return err
+	}
+
+	if unavailable != nil {
+		task.Step().Errorf("Warning: %s, relying on the checksum of the release list", unavailable)
+	}
+
+	return nil
+}
+
+// verifyArchive is a function that verifies a cached archive like verifyDownload does. If it was verified against the
+// checksum file of an independent origin, this is recorded next to the archive, so that reusing it later on does not depend
+// on the independent origin being reachable.
+func (m *GoManager) verifyArchive(file releases.ReleaseFile, downloadedArchive string, independent bool) error {
+	if err := verifyDownload(file, downloadedArchive, m.PinnedChecksums, independent); err != nil {
+		return err
+	}
+	if !independent {
+		return nil
+	}
+
+	return ioutil.WriteFile(downloadedArchive+verifiedSuffix, []byte(file.Sha256), 0600)
+}
+
+// isVerifiedIndependently is a function that checks if it was recorded, that a cached archive with the given checksum was
+// verified against the checksum file of an independent origin.
+func isVerifiedIndependently(downloadedArchive, checksum string) bool {
+	recorded, err := ioutil.ReadFile(downloadedArchive + verifiedSuffix)
+	return err == nil && strings.TrimSpace(string(recorded)) == checksum
+}
+
+// publish is a function that verifies an installation, that was extracted into a staging directory, records its manifest and
+// moves it to its final location. The given manifest only needs to describe the origin of the installation, since its files
+// are added here.
+func (m *GoManager) publish(
+	task *tasks.Task,
+	extractionDirectory, sdkDirectory string,
+	versionNumber *version.Version,
+	manifest *Manifest,
+) error {
 	verifyDescription := "Verifying installation"
 	verifyFunction := func() error { return verifyRelease(versionNumber, extractionDirectory) }
-	if err := installTask.Track(verifyDescription, verifyFunction); err != nil {
+	if err := task.Track(verifyDescription, verifyFunction); err != nil {
+		return err
+	}
+
+	manifestDescription := "Recording installation manifest"
+	manifestFunction := func() error {
+		files, err := scanFiles(filepath.Join(extractionDirectory, "go"))
+		if err != nil {
+			return err
+		}
+
+		manifest.InstalledAt = time.Now().UTC()
+		manifest.Files = files
+		return writeManifest(sdkDirectory, manifest)
+	}
+	if err := task.Track(manifestDescription, manifestFunction); err != nil {
 		return err
 	}
 
+	// The staging directory resides in the root directory, so the installation is published by a single rename. This way, an
+	// interrupted installation never leaves an incomplete installation behind, that would be detected as a valid one.
 	moveDescription := "Moving installation to final location"
-	moveFunction := func() error { return fileutil.MoveDirectory(filepath.Join(extractionDirectory, "go"), sdkDirectory) }
-	if err := installTask.Track(moveDescription, moveFunction); err != nil {
-		fileutil.TryRemove(sdkDirectory)
+	moveFunction := func() error { return os.Rename(filepath.Join(extractionDirectory, "go"), sdkDirectory) }
+	if err := task.Track(moveDescription, moveFunction); err != nil {
+		fileutil.TryRemove(manifestPath(sdkDirectory))
 		return err
 	}
 
+	return nil
+}
+
+func (m *GoManager) addInstallation(versionNumber *version.Version, operatingSystem, arch string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if !isNativePlatform(operatingSystem, arch) {
+		m.ForeignInstallations = append(m.ForeignInstallations, Installation{
+			Version:   versionNumber,
+			OS:        operatingSystem,
+			Arch:      arch,
+			Directory: m.SDKDirectoryForPlatform(versionNumber, operatingSystem, arch),
+		})
+		return
+	}
+
 	m.InstalledVersions = append(m.InstalledVersions, versionNumber)
 	sort.Sort(m.InstalledVersions)
+}
 
-	return nil
+func uniqueVersions(versionNumbers version.Collection) version.Collection {
+	var unique version.Collection
+
+	for _, versionNumber := range versionNumbers {
+		duplicate := false
+		for _, uniqueVersion := range unique {
+			duplicate = duplicate || uniqueVersion.Equal(versionNumber)
+		}
+
+		if !duplicate {
+			unique = append(unique, versionNumber)
+		}
+	}
+
+	return unique
 }
 
-func downloadRelease(file releases.ReleaseFile, destinationFile string) error {
-	downloaded, err := httputil.GetFile(file.GetURL(), destinationFile, false)
+func downloadRelease(file releases.ReleaseFile, destinationFile string, progress func(done int64)) error {
+	downloaded, err := httputil.GetFileWithProgress(file.GetURL(), destinationFile, false, progress)
 	if err != nil {
 		return err
 	}
@@ -96,20 +366,45 @@ func downloadRelease(file releases.ReleaseFile, destinationFile string) error {
 	return nil
 }
 
-func verifyDownload(file releases.ReleaseFile, destinationFile string) error {
-	same, err := file.VerifySame(destinationFile)
+func verifyDownload(
+	file releases.ReleaseFile,
+	destinationFile string,
+	pinnedChecksums *PinnedChecksums,
+	independent bool,
+) error {
+	checksum, err := releases.Checksum(destinationFile)
 	if err != nil {
 		return err
 	}
-	if !same {
-		return fmt.Errorf("downloaded file %s could not be verified because the checksums did not match", destinationFile)
+	if file.Sha256 != checksum {
+		return NewError(
+			ErrChecksumMismatch,
+			"downloaded file %s could not be verified because the checksums did not match", destinationFile,
+		)
+	}
+
+	if err := pinnedChecksums.Verify(file, checksum); err != nil {
+		return err
+	}
+
+	if independent {
+		independentChecksum, err := file.FetchChecksum()
+		if err != nil {
+			return NewError(errChecksumUnavailable, "could not retrieve independent checksum of %s: %w", file.Filename, err)
+		}
+		if independentChecksum != checksum {
+			return NewError(
+				ErrChecksumMismatch,
+				"checksum of %s does not match the checksum served at %s", file.Filename, file.GetChecksumURL(),
+			)
+		}
 	}
 
 	return nil
 }
 
-func extractRelease(destinationFile string, destinationDirectory string) error {
-	extracted, err := archiveutil.Extract(destinationFile, destinationDirectory, false)
+func extractRelease(destinationFile string, destinationDirectory string, progress func(done int64)) error {
+	extracted, err := archiveutil.ExtractWithProgress(destinationFile, destinationDirectory, false, progress)
 	if err != nil {
 		return err
 	}
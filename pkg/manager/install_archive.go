@@ -0,0 +1,124 @@
+package manager
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+
+	"github.com/jangraefen/go-man/internal/fileutil"
+	"github.com/jangraefen/go-man/pkg/releases"
+	"github.com/jangraefen/go-man/pkg/tasks"
+)
+
+// InstallArchive is a function that installs a new instance of the Go SDK from a local archive file, like a binary
+// distribution that was obtained from an artifact store. Neither the release list nor the network are used, so the version
+// and the platform are detected from the extracted installation. If a checksum is given, the archive has to match it.
+func (m *GoManager) InstallArchive(archiveFile, checksum string) (*Installation, error) {
+	unlock, err := m.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	installation, err := m.installArchive(m.task, archiveFile, checksum)
+	if err != nil {
+		return nil, err
+	}
+
+	m.addInstallation(installation.Version, installation.OS, installation.Arch)
+	return installation, nil
+}
+
+func (m *GoManager) installArchive(task *tasks.Task, archiveFile, checksum string) (*Installation, error) {
+	task.Printf("Installing %s:", archiveFile)
+	installTask := task.Step()
+
+	fileInfo, err := os.Stat(archiveFile)
+	if err != nil {
+		return nil, err
+	}
+	if !fileInfo.Mode().IsRegular() {
+		return nil, fmt.Errorf("archive %s is not a regular file", archiveFile)
+	}
+
+	file := releases.ReleaseFile{
+		Filename: filepath.Base(archiveFile),
+		Size:     releaseFileSize(fileInfo.Size()),
+		Kind:     releases.ArchiveFile,
+	}
+	extractionDirectory := filepath.Join(m.RootDirectory, stagingDirectoryPrefix+file.Filename)
+
+	// A staging directory that is left behind by an interrupted installation would prevent the extraction.
+	fileutil.TryRemove(extractionDirectory)
+	defer fileutil.TryRemove(extractionDirectory)
+
+	checksumDescription := "Verifying archive integrity"
+	checksumFunction := func() error {
+		actualChecksum, err := releases.Checksum(archiveFile)
+		if err != nil {
+			return err
+		}
+		if checksum != "" && !strings.EqualFold(checksum, actualChecksum) {
+			return NewError(ErrChecksumMismatch, "archive %s could not be verified because the checksums did not match", archiveFile)
+		}
+
+		file.Sha256 = actualChecksum
+		return m.PinnedChecksums.Verify(file, actualChecksum)
+	}
+	if err := installTask.Track(checksumDescription, checksumFunction); err != nil {
+		return nil, err
+	}
+
+	extractDescription := "Extracting distribution"
+	extractFunction := func(progress *tasks.Progress) error {
+		return extractRelease(archiveFile, extractionDirectory, progress.Report)
+	}
+	if err := installTask.TrackProgress(extractDescription, fileInfo.Size(), extractFunction); err != nil {
+		return nil, err
+	}
+
+	var versionNumber *version.Version
+	detectDescription := "Detecting installed version"
+	detectFunction := func() error {
+		versionNumber, err = detectGoVersion(filepath.Join(extractionDirectory, "go"))
+		if err != nil {
+			return fmt.Errorf("archive %s does not contain a Go distribution: %w", archiveFile, err)
+		}
+
+		return nil
+	}
+	if err := installTask.Track(detectDescription, detectFunction); err != nil {
+		return nil, err
+	}
+
+	operatingSystem, arch := detectGoPlatform(filepath.Join(extractionDirectory, "go"), versionNumber)
+	installTask.Printf("Detected version %s for %s-%s", versionNumber, operatingSystem, arch)
+
+	sdkDirectory := m.SDKDirectoryForPlatform(versionNumber, operatingSystem, arch)
+	if fileutil.PathExists(sdkDirectory) {
+		return nil, NewError(ErrAlreadyInstalled, "installation skipped, since %s is already present", sdkDirectory)
+	}
+
+	file.Version = "go" + versionNumber.Original()
+	manifest := &Manifest{Version: file.Version, Archive: file}
+
+	if err := m.publish(installTask, extractionDirectory, sdkDirectory, versionNumber, manifest); err != nil {
+		return nil, err
+	}
+
+	return &Installation{Version: versionNumber, OS: operatingSystem, Arch: arch, Directory: sdkDirectory}, nil
+}
+
+// releaseFileSize is a function that converts the size of an archive to the size of a release file. Since the release list
+// stores sizes as 32-bit integers, the size of an archive of 2 GiB or more is omitted instead of overflowing.
+func releaseFileSize(size int64) int32 {
+	if size > math.MaxInt32 {
+		return 0
+	}
+
+	return int32(size)
+}
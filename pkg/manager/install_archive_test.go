@@ -0,0 +1,114 @@
+package manager
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jangraefen/go-man/pkg/releases"
+	"github.com/jangraefen/go-man/pkg/tasks"
+)
+
+func TestGoManager_InstallArchive(t *testing.T) {
+	expectedVersion := version.Must(version.NewVersion("1.15.2"))
+	tempDir := t.TempDir()
+
+	sut, err := NewManager(&tasks.Task{
+		Output: ioutil.Discard,
+		Error:  ioutil.Discard,
+	}, tempDir)
+	require.NoError(t, err)
+
+	// Nothing must be retrieved from the release source.
+	releases.ReleaseListCache[releases.IncludeAll] = releases.Collection{}
+	t.Cleanup(func() {
+		delete(releases.ReleaseListCache, releases.IncludeAll)
+	})
+
+	archiveFile := filepath.Join(t.TempDir(), "go1.15.2.linux-amd64.zip")
+	writeDistribution(t, archiveFile, "go1.15.2")
+
+	content, err := ioutil.ReadFile(archiveFile)
+	require.NoError(t, err)
+	checksum := fmt.Sprintf("%x", sha256.Sum256(content))
+
+	_, err = sut.InstallArchive(archiveFile, helloChecksum)
+	assert.Error(t, err)
+	assert.NoDirExists(t, filepath.Join(tempDir, "go1.15.2"))
+
+	installation, err := sut.InstallArchive(archiveFile, checksum)
+	require.NoError(t, err)
+	assert.Equal(t, &Installation{
+		Version:   expectedVersion,
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		Directory: filepath.Join(tempDir, "go1.15.2"),
+	}, installation)
+	versionNumber := installation.Version
+	assert.FileExists(t, filepath.Join(tempDir, "go1.15.2", "VERSION"))
+	assert.Equal(t, version.Collection{expectedVersion}, sut.InstalledVersions)
+	assert.NoDirExists(t, filepath.Join(tempDir, stagingDirectoryPrefix+"go1.15.2.linux-amd64.zip"))
+
+	manifest, err := sut.Manifest(versionNumber)
+	require.NoError(t, err)
+	assert.Equal(t, "go1.15.2", manifest.Version)
+	assert.Equal(t, "go1.15.2.linux-amd64.zip", manifest.Archive.Filename)
+	assert.Equal(t, checksum, manifest.Archive.Sha256)
+
+	_, err = sut.InstallArchive(archiveFile, "")
+	assert.Error(t, err)
+
+	require.NoError(t, sut.Uninstall(versionNumber))
+	sut.PinnedChecksums = &PinnedChecksums{
+		Path:      ".gmn.sum",
+		Checksums: map[string]string{"go1.15.2.linux-amd64.zip": helloChecksum},
+	}
+	_, err = sut.InstallArchive(archiveFile, "")
+	assert.Error(t, err)
+
+	sut.PinnedChecksums = nil
+	_, err = sut.InstallArchive(archiveFile, "")
+	assert.NoError(t, err)
+}
+
+func TestGoManager_InstallArchive_WithInvalidArchive(t *testing.T) {
+	tempDir := t.TempDir()
+
+	sut, err := NewManager(&tasks.Task{
+		Output: ioutil.Discard,
+		Error:  ioutil.Discard,
+	}, tempDir)
+	require.NoError(t, err)
+
+	_, err = sut.InstallArchive(filepath.Join(t.TempDir(), "missing.zip"), "")
+	assert.Error(t, err)
+
+	_, err = sut.InstallArchive(t.TempDir(), "")
+	assert.Error(t, err)
+
+	_, err = sut.InstallArchive(getTestFile(t, "invalid.zip"), "")
+	assert.Error(t, err)
+
+	archiveFile := filepath.Join(t.TempDir(), "empty.zip")
+	writeDistribution(t, archiveFile, "not a version")
+	_, err = sut.InstallArchive(archiveFile, "")
+	assert.Error(t, err)
+
+	assert.Empty(t, sut.InstalledVersions)
+}
+
+func TestReleaseFileSize(t *testing.T) {
+	assert.Equal(t, int32(0), releaseFileSize(0))
+	assert.Equal(t, int32(1024), releaseFileSize(1024))
+	assert.Equal(t, int32(math.MaxInt32), releaseFileSize(math.MaxInt32))
+	assert.Equal(t, int32(0), releaseFileSize(math.MaxInt32+1))
+	assert.Equal(t, int32(0), releaseFileSize(5<<30))
+}
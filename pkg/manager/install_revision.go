@@ -0,0 +1,207 @@
+package manager
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+
+	"github.com/jangraefen/go-man/internal/fileutil"
+	"github.com/jangraefen/go-man/pkg/tasks"
+)
+
+const (
+	// DefaultGitRemote is the git remote that the source code of Go is fetched from, if no other remote is configured.
+	DefaultGitRemote = "https://go.googlesource.com/go"
+
+	tipName            = "tip"
+	tipRef             = "master"
+	gitPrefix          = "git:"
+	revisionHashLength = 12
+)
+
+var (
+	goVersionPattern = regexp.MustCompile(`(?m)^const Version = (\d+)$`)
+)
+
+// ParseRevision is a function that checks if a given name requests a git revision instead of a released version.
+// The name "tip" requests the latest revision of the main branch, while names like "git:<ref>" request any branch, tag or
+// commit hash. If the name requests a git revision, its ref is returned.
+func ParseRevision(name string) (string, bool) {
+	if name == tipName {
+		return tipRef, true
+	}
+
+	if strings.HasPrefix(name, gitPrefix) {
+		return strings.TrimPrefix(name, gitPrefix), true
+	}
+
+	return "", false
+}
+
+// InstallRevision is a function that builds a new instance of the Go SDK from a git revision and installs it.
+// The ref is fetched from the given git remote, which may also be a local clone, and built with an installed version as
+// bootstrap, like InstallSource does. The installed version is named after the upcoming release and the fetched commit, like
+// "1.22-devel.0123456789ab".
+func (m *GoManager) InstallRevision(remote, ref string) (*version.Version, error) {
+	unlock, err := m.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	versionNumber, err := m.installRevision(m.task, remote, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	m.addInstallation(versionNumber, runtime.GOOS, runtime.GOARCH)
+	return versionNumber, nil
+}
+
+//nolint:funlen
+func (m *GoManager) installRevision(task *tasks.Task, remote, ref string) (*version.Version, error) {
+	if ref == "" {
+		return nil, fmt.Errorf("no git ref given to build from %s", remote)
+	}
+
+	// A local clone may be given by a relative path, which would not be found once git runs inside the staging directory.
+	if fileutil.PathExists(remote) {
+		absoluteRemote, err := filepath.Abs(remote)
+		if err != nil {
+			return nil, err
+		}
+
+		remote = absoluteRemote
+	}
+
+	task.Printf("Building %s from %s:", ref, remote)
+	installTask := task.Step()
+
+	// The git directory is kept apart from the working tree, so that it does not become a part of the installation.
+	extractionDirectory := filepath.Join(m.RootDirectory, stagingDirectoryPrefix+"git")
+	sourceDirectory := filepath.Join(extractionDirectory, "go")
+	gitDirectory := filepath.Join(extractionDirectory, "git")
+
+	// A staging directory that is left behind by an interrupted installation would prevent the checkout.
+	fileutil.TryRemove(extractionDirectory)
+	defer fileutil.TryRemove(extractionDirectory)
+
+	var commit string
+	fetchDescription := "Fetching revision"
+	fetchFunction := func() error {
+		if err := os.MkdirAll(sourceDirectory, 0755); err != nil {
+			return err
+		}
+
+		if _, err := runGit(gitDirectory, sourceDirectory, "init", "--quiet"); err != nil {
+			return err
+		}
+		if _, err := runGit(gitDirectory, sourceDirectory, "fetch", "--quiet", "--depth", "1", remote, ref); err != nil {
+			return err
+		}
+		if _, err := runGit(gitDirectory, sourceDirectory, "checkout", "--quiet", "--detach", "FETCH_HEAD"); err != nil {
+			return err
+		}
+
+		var err error
+		commit, err = runGit(gitDirectory, sourceDirectory, "rev-parse", "HEAD")
+		return err
+	}
+	if err := installTask.Track(fetchDescription, fetchFunction); err != nil {
+		return nil, err
+	}
+
+	var versionNumber *version.Version
+	var versionLine string
+	detectDescription := "Detecting development version"
+	detectFunction := func() error {
+		minorVersion, err := detectUpcomingMinorVersion(sourceDirectory)
+		if err != nil {
+			return err
+		}
+
+		// Go reports the content of the VERSION file as its version, so the build is named like Go names its own development
+		// builds. Without a VERSION file, the build would derive the same from the git directory, which is not available.
+		versionLine = fmt.Sprintf("%sgo1.%d-%s", develPrefix, minorVersion, commit[:revisionHashLength])
+		versionFile := filepath.Join(sourceDirectory, "VERSION")
+		if err := ioutil.WriteFile(versionFile, []byte(versionLine), 0644); err != nil { //nolint:gosec
+			return err
+		}
+
+		versionNumber, err = detectGoVersion(sourceDirectory)
+		return err
+	}
+	if err := installTask.Track(detectDescription, detectFunction); err != nil {
+		return nil, err
+	}
+
+	installTask.Printf("Detected version %s of commit %s", versionNumber, commit)
+	sdkDirectory := m.SDKDirectory(versionNumber)
+	if fileutil.PathExists(sdkDirectory) {
+		return nil, NewError(ErrAlreadyInstalled, "installation skipped, since %s is already present", sdkDirectory)
+	}
+
+	bootstrapDirectory, err := m.bootstrapDirectory(versionNumber)
+	if err != nil {
+		return nil, err
+	}
+	installTask.Printf("Bootstrapping with %s", bootstrapDirectory)
+
+	buildDescription := "Building distribution"
+	buildFunction := func() error { return buildRelease(sourceDirectory, bootstrapDirectory, sdkDirectory) }
+	if err := installTask.Track(buildDescription, buildFunction); err != nil {
+		return nil, err
+	}
+
+	manifest := &Manifest{
+		Version:  versionLine,
+		Revision: &ManifestRevision{Remote: remote, Ref: ref, Commit: commit},
+	}
+	if err := m.publish(installTask, extractionDirectory, sdkDirectory, versionNumber, manifest); err != nil {
+		return nil, err
+	}
+
+	return versionNumber, nil
+}
+
+// detectUpcomingMinorVersion is a function that reads the minor version of the upcoming Go release from its source code.
+func detectUpcomingMinorVersion(sourceDirectory string) (int, error) {
+	content, err := ioutil.ReadFile(filepath.Join(sourceDirectory, "src", "internal", "goversion", "goversion.go"))
+	if err != nil {
+		return 0, fmt.Errorf("source code does not name its upcoming version: %w", err)
+	}
+
+	matches := goVersionPattern.FindSubmatch(content)
+	if matches == nil {
+		return 0, fmt.Errorf("source code does not name its upcoming version")
+	}
+
+	var minorVersion int
+	_, err = fmt.Sscanf(string(matches[1]), "%d", &minorVersion)
+	return minorVersion, err
+}
+
+// runGit is a function that runs a git command on a repository, whose git directory and working tree are kept apart.
+func runGit(gitDirectory, workTree string, args ...string) (string, error) {
+	output := &bytes.Buffer{}
+
+	command := exec.Command("git", args...) //nolint:gosec
+	command.Dir = workTree
+	command.Env = append(os.Environ(), "GIT_DIR="+gitDirectory, "GIT_WORK_TREE="+workTree)
+	command.Stdout = output
+	command.Stderr = output
+
+	if err := command.Run(); err != nil {
+		return "", fmt.Errorf("git %s failed: %w\n%s", args[0], err, strings.TrimSpace(output.String()))
+	}
+
+	return strings.TrimSpace(output.String()), nil
+}
@@ -0,0 +1,139 @@
+package manager
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jangraefen/go-man/pkg/tasks"
+)
+
+func TestParseRevision(t *testing.T) {
+	ref, isRevision := ParseRevision("tip")
+	assert.True(t, isRevision)
+	assert.Equal(t, "master", ref)
+
+	ref, isRevision = ParseRevision("git:release-branch.go1.21")
+	assert.True(t, isRevision)
+	assert.Equal(t, "release-branch.go1.21", ref)
+
+	ref, isRevision = ParseRevision("git:")
+	assert.True(t, isRevision)
+	assert.Empty(t, ref)
+
+	_, isRevision = ParseRevision("1.15.2")
+	assert.False(t, isRevision)
+}
+
+func TestGoManager_InstallRevision(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil || runtime.GOOS == "windows" {
+		t.Skip("git and bash are needed to fetch and build a revision")
+	}
+
+	remote := t.TempDir()
+	firstCommit := setupRemote(t, remote, "21")
+	secondCommit := setupRemote(t, remote, "22")
+
+	tempDir := t.TempDir()
+	setupInstallation(t, tempDir, true, "1.21.3")
+
+	sut, err := NewManager(&tasks.Task{
+		Output: ioutil.Discard,
+		Error:  ioutil.Discard,
+	}, tempDir)
+	require.NoError(t, err)
+
+	versionNumber, err := sut.InstallRevision(remote, "master")
+	require.NoError(t, err)
+	assert.Equal(t, version.Must(version.NewVersion("1.22-devel."+secondCommit[:12])), versionNumber)
+	assert.Contains(t, sut.InstalledVersions, versionNumber)
+	assert.NoDirExists(t, filepath.Join(sut.SDKDirectory(versionNumber), ".git"))
+	assert.FileExists(t, filepath.Join(sut.SDKDirectory(versionNumber), "bin", "go"))
+
+	manifest, err := sut.Manifest(versionNumber)
+	require.NoError(t, err)
+	assert.Equal(t, "devel go1.22-"+secondCommit[:12], manifest.Version)
+	assert.Equal(t, &ManifestRevision{Remote: remote, Ref: "master", Commit: secondCommit}, manifest.Revision)
+
+	_, err = sut.InstallRevision(remote, "master")
+	assert.Error(t, err)
+
+	versionNumber, err = sut.InstallRevision(remote, "v21")
+	require.NoError(t, err)
+	assert.Equal(t, version.Must(version.NewVersion("1.21-devel."+firstCommit[:12])), versionNumber)
+
+	_, err = sut.InstallRevision(remote, "missing")
+	assert.Error(t, err)
+
+	_, err = sut.InstallRevision(remote, "")
+	assert.Error(t, err)
+
+	// Other processes must still detect the installations.
+	detected, err := NewManager(sut.task, tempDir)
+	require.NoError(t, err)
+	assert.Len(t, detected.InstalledVersions, 3)
+}
+
+func TestDetectUpcomingMinorVersion(t *testing.T) {
+	sourceDirectory := t.TempDir()
+
+	_, err := detectUpcomingMinorVersion(sourceDirectory)
+	assert.Error(t, err)
+
+	versionFile := filepath.Join(sourceDirectory, "src", "internal", "goversion", "goversion.go")
+	require.NoError(t, os.MkdirAll(filepath.Dir(versionFile), 0700))
+	require.NoError(t, ioutil.WriteFile(versionFile, []byte("package goversion\n"), 0600))
+
+	_, err = detectUpcomingMinorVersion(sourceDirectory)
+	assert.Error(t, err)
+
+	require.NoError(t, ioutil.WriteFile(versionFile, []byte("package goversion\n\nconst Version = 22\n"), 0600))
+
+	minorVersion, err := detectUpcomingMinorVersion(sourceDirectory)
+	assert.NoError(t, err)
+	assert.Equal(t, 22, minorVersion)
+}
+
+// setupRemote is a function that commits a fake Go source tree with the given upcoming minor version to a git repository.
+// The commit is tagged with the minor version and becomes the head of the master branch. Its hash is returned.
+func setupRemote(t *testing.T, remote, minorVersion string) string {
+	t.Helper()
+
+	versionFile := filepath.Join(remote, "src", "internal", "goversion", "goversion.go")
+	require.NoError(t, os.MkdirAll(filepath.Dir(versionFile), 0700))
+	require.NoError(t, ioutil.WriteFile(versionFile, []byte("package goversion\n\nconst Version = "+minorVersion+"\n"), 0600))
+	require.NoError(t, ioutil.WriteFile(
+		filepath.Join(remote, "src", "make.bash"),
+		[]byte(`mkdir -p ../bin && echo "$GOROOT_BOOTSTRAP" > ../bin/go`),
+		0600,
+	))
+
+	gitDirectory := filepath.Join(remote, ".git")
+	if _, err := os.Stat(gitDirectory); os.IsNotExist(err) {
+		_, err := runGit(gitDirectory, remote, "init", "--quiet")
+		require.NoError(t, err)
+		_, err = runGit(gitDirectory, remote, "symbolic-ref", "HEAD", "refs/heads/master")
+		require.NoError(t, err)
+	}
+
+	for _, args := range [][]string{
+		{"add", "--all"},
+		{"-c", "user.name=gmn", "-c", "user.email=gmn@example.org", "commit", "--quiet", "--message", minorVersion},
+		{"tag", "v" + minorVersion},
+	} {
+		_, err := runGit(gitDirectory, remote, args...)
+		require.NoError(t, err)
+	}
+
+	commit, err := runGit(gitDirectory, remote, "rev-parse", "HEAD")
+	require.NoError(t, err)
+
+	return commit
+}
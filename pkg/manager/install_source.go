@@ -0,0 +1,248 @@
+package manager
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+
+	"github.com/jangraefen/go-man/internal/fileutil"
+	"github.com/jangraefen/go-man/pkg/releases"
+	"github.com/jangraefen/go-man/pkg/tasks"
+)
+
+const (
+	// BootstrapVariable is the name of the environment variable that names the Go SDK, which builds Go from source.
+	BootstrapVariable = "GOROOT_BOOTSTRAP"
+
+	buildLogLines = 20
+)
+
+var (
+	// The oldest versions of Go, that are able to bootstrap the build of a minor version and all later ones. Releases before
+	// Go 1.5 are built with a C compiler instead. See https://go.dev/doc/install/source#bootstrapFromSource for details.
+	bootstrapRequirements = []struct {
+		minor     int64
+		bootstrap string
+	}{
+		{minor: 26, bootstrap: "1.24.6"},
+		{minor: 24, bootstrap: "1.22.6"},
+		{minor: 22, bootstrap: "1.20"},
+		{minor: 20, bootstrap: "1.17.13"},
+		{minor: 5, bootstrap: "1.4"},
+	}
+)
+
+// InstallSource is a function that builds new instances of the Go SDK from their source archives and installs them.
+// The source archive is downloaded like any other release file. The build is bootstrapped with the Go SDK named by the
+// GOROOT_BOOTSTRAP environment variable or, if absent, with the highest installed release. Since Go is built for the current
+// platform, no operating system and platform architecture can be chosen. A failing build never stops the others, but all
+// failures are reported in the returned error, like InstallAll does. Feedback is reported to the task of the manager, while
+// failures are returned as errors.
+func (m *GoManager) InstallSource(versionNumbers version.Collection, releaseType releases.ReleaseType) error {
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	var failedVersions version.Collection
+	var failures []error
+
+	// Builds are not run concurrently, since each of them already uses all available processors. Like with InstallAll, a
+	// failing build does not stop the others.
+	for _, versionNumber := range uniqueVersions(versionNumbers) {
+		if err := m.installSource(m.task, versionNumber, releaseType); err != nil {
+			failedVersions = append(failedVersions, versionNumber)
+			failures = append(failures, err)
+			continue
+		}
+
+		m.addInstallation(versionNumber, runtime.GOOS, runtime.GOARCH)
+	}
+
+	return installationFailure(failedVersions, failures)
+}
+
+func (m *GoManager) installSource(task *tasks.Task, versionNumber *version.Version, releaseType releases.ReleaseType) error {
+	task.Printf("Building %s from source:", versionNumber)
+	installTask := task.Step()
+
+	release, releasePresent, err := releases.GetForVersion(releaseType, versionNumber)
+	if err != nil {
+		return err
+	}
+	if !releasePresent {
+		return NewError(ErrReleaseNotFound, "release with version %s not present", versionNumber)
+	}
+
+	files := release.FindFiles("", "", releases.SourceFile)
+	if len(files) != 1 {
+		return NewError(ErrPlatformUnavailable, "release %s has no source archive", versionNumber)
+	}
+
+	file := files[0]
+	downloadedArchive := m.cachedArchivePath(file)
+	extractionDirectory := filepath.Join(m.RootDirectory, stagingDirectoryPrefix+file.Version)
+	sdkDirectory := m.SDKDirectory(versionNumber)
+
+	if fileutil.PathExists(sdkDirectory) {
+		return NewError(ErrAlreadyInstalled, "installation skipped, since %s is already present", sdkDirectory)
+	}
+
+	bootstrapDirectory, err := m.bootstrapDirectory(versionNumber)
+	if err != nil {
+		return err
+	}
+	installTask.Printf("Bootstrapping with %s", bootstrapDirectory)
+
+	// A staging directory that is left behind by an interrupted installation would prevent the extraction.
+	fileutil.TryRemove(extractionDirectory)
+	defer fileutil.TryRemove(extractionDirectory)
+
+	if err := m.fetchArchive(installTask, file); err != nil {
+		return err
+	}
+
+	extractDescription := "Extracting sources"
+	extractFunction := func(progress *tasks.Progress) error {
+		return extractRelease(downloadedArchive, extractionDirectory, progress.Report)
+	}
+	if err := installTask.TrackProgress(extractDescription, int64(file.Size), extractFunction); err != nil {
+		return err
+	}
+
+	buildDescription := "Building distribution"
+	buildFunction := func() error {
+		return buildRelease(filepath.Join(extractionDirectory, "go"), bootstrapDirectory, sdkDirectory)
+	}
+	if err := installTask.Track(buildDescription, buildFunction); err != nil {
+		return err
+	}
+
+	return m.publish(installTask, extractionDirectory, sdkDirectory, versionNumber, newManifest(release, file))
+}
+
+// bootstrapDirectory is a function that determines the Go SDK, that builds another version of Go from source.
+// The highest installed release is preferred over development builds, since these might be broken. Since each version of Go
+// requires a minimum version to bootstrap its build, a bootstrap SDK that is too old is rejected before anything is built.
+func (m *GoManager) bootstrapDirectory(versionNumber *version.Version) (string, error) {
+	minimumVersion := minimumBootstrapVersion(versionNumber)
+
+	if bootstrapDirectory := os.Getenv(BootstrapVariable); bootstrapDirectory != "" {
+		// A bootstrap SDK without a detectable version is used anyway, since it might be a custom build.
+		bootstrapVersion, err := detectGoVersion(bootstrapDirectory)
+		if err == nil && minimumVersion != nil && bootstrapVersion.LessThan(minimumVersion) {
+			return "", fmt.Errorf(
+				"building %s requires Go %s or later to bootstrap, but %s contains %s",
+				versionNumber, minimumVersion, BootstrapVariable, bootstrapVersion,
+			)
+		}
+
+		return bootstrapDirectory, nil
+	}
+
+	installedVersions := make(version.Collection, len(m.InstalledVersions))
+	copy(installedVersions, m.InstalledVersions)
+	sort.Sort(sort.Reverse(installedVersions))
+
+	var bootstrapVersion *version.Version
+	for _, installedVersion := range installedVersions {
+		if installedVersion.Equal(versionNumber) {
+			continue
+		}
+		if !isDevelVersion(installedVersion) {
+			bootstrapVersion = installedVersion
+			break
+		}
+		if bootstrapVersion == nil {
+			bootstrapVersion = installedVersion
+		}
+	}
+
+	if bootstrapVersion == nil {
+		return "", fmt.Errorf(
+			"no installed Go SDK available to bootstrap the build, install a release or set %s", BootstrapVariable,
+		)
+	}
+	if minimumVersion != nil && bootstrapVersion.LessThan(minimumVersion) {
+		return "", fmt.Errorf(
+			"building %s requires Go %s or later to bootstrap, but only %s is installed, install a newer release or set %s",
+			versionNumber, minimumVersion, bootstrapVersion, BootstrapVariable,
+		)
+	}
+
+	return m.SDKDirectory(bootstrapVersion), nil
+}
+
+// minimumBootstrapVersion is a function that returns the oldest version of Go, that is able to bootstrap the build of a
+// given version. Might be nil, if the given version does not need to be bootstrapped with Go.
+func minimumBootstrapVersion(versionNumber *version.Version) *version.Version {
+	segments := versionNumber.Segments64()
+	if segments[0] != 1 {
+		return nil
+	}
+
+	for _, requirement := range bootstrapRequirements {
+		if segments[1] >= requirement.minor {
+			return version.Must(version.NewVersion(requirement.bootstrap))
+		}
+	}
+
+	return nil
+}
+
+func buildRelease(sourceDirectory, bootstrapDirectory, sdkDirectory string) error {
+	var command *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		command = exec.Command("cmd", "/c", "make.bat") //nolint:gosec
+	case "plan9":
+		command = exec.Command("rc", "make.rc") //nolint:gosec
+	default:
+		command = exec.Command("bash", "make.bash") //nolint:gosec
+	}
+
+	output := &bytes.Buffer{}
+	command.Dir = filepath.Join(sourceDirectory, "src")
+	command.Env = buildEnvironment(bootstrapDirectory, sdkDirectory, os.Environ())
+	command.Stdout = output
+	command.Stderr = output
+
+	if err := command.Run(); err != nil {
+		return fmt.Errorf("build failed: %w\n%s", err, lastLines(output.String(), buildLogLines))
+	}
+
+	return nil
+}
+
+func buildEnvironment(bootstrapDirectory, sdkDirectory string, environment []string) []string {
+	result := make([]string, 0, len(environment)+2)
+
+	for _, variable := range environment {
+		key := strings.SplitN(variable, "=", 2)[0]
+		if isVariable(key, "GOROOT") || isVariable(key, BootstrapVariable) || isVariable(key, "GOROOT_FINAL") {
+			continue
+		}
+
+		result = append(result, variable)
+	}
+
+	// Older releases embed the location, that the distribution is built in, unless they are told about its final location.
+	return append(result, BootstrapVariable+"="+bootstrapDirectory, "GOROOT_FINAL="+sdkDirectory)
+}
+
+func lastLines(text string, count int) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if len(lines) > count {
+		lines = lines[len(lines)-count:]
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,253 @@
+package manager
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jangraefen/go-man/pkg/releases"
+	"github.com/jangraefen/go-man/pkg/tasks"
+)
+
+func TestGoManager_InstallSource(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake build script is a bash script")
+	}
+
+	bootstrapVersion := version.Must(version.NewVersion("1.14.9"))
+	versionNumber := version.Must(version.NewVersion("1.15.2"))
+	tempDir := t.TempDir()
+
+	sut, err := NewManager(&tasks.Task{
+		Output: ioutil.Discard,
+		Error:  ioutil.Discard,
+	}, tempDir)
+	require.NoError(t, err)
+
+	setupCachedSourceRelease(t, sut, versionNumber, "exit 1")
+	assert.Error(t, sut.InstallSource(version.Collection{versionNumber}, releases.IncludeAll))
+
+	setupInstallation(t, tempDir, true, "1.14.9")
+	sut.InstalledVersions = version.Collection{bootstrapVersion}
+
+	assert.Error(t, sut.InstallSource(version.Collection{versionNumber}, releases.IncludeAll))
+	assert.NoDirExists(t, filepath.Join(tempDir, "go1.15.2"))
+
+	delete(releases.ReleaseListCache, releases.IncludeAll)
+	file := setupCachedSourceRelease(t, sut, versionNumber, `mkdir -p ../bin && echo "$GOROOT_BOOTSTRAP" > ../bin/go`)
+	assert.NoError(t, sut.InstallSource(version.Collection{versionNumber, versionNumber}, releases.IncludeAll))
+	assert.Equal(t, version.Collection{bootstrapVersion, versionNumber}, sut.InstalledVersions)
+
+	bootstrapDirectory, err := ioutil.ReadFile(filepath.Join(tempDir, "go1.15.2", "bin", "go"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(tempDir, "go1.14.9")+"\n", string(bootstrapDirectory))
+
+	manifest, err := sut.Manifest(versionNumber)
+	require.NoError(t, err)
+	assert.Equal(t, file, manifest.Archive)
+
+	assert.Error(t, sut.InstallSource(version.Collection{versionNumber}, releases.IncludeAll))
+
+	// A failing build does not stop the builds of the other versions.
+	missingVersion := version.Must(version.NewVersion("1.99.0"))
+	otherVersion := version.Must(version.NewVersion("1.15.3"))
+	setupCachedSourceRelease(t, sut, otherVersion, `mkdir -p ../bin && echo "$GOROOT_BOOTSTRAP" > ../bin/go`)
+
+	err = sut.InstallSource(version.Collection{missingVersion, otherVersion}, releases.IncludeAll)
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrReleaseNotFound))
+	assert.Contains(t, err.Error(), "1.99.0")
+	assert.DirExists(t, filepath.Join(tempDir, "go1.15.3"))
+	assert.Equal(t, version.Collection{bootstrapVersion, versionNumber, otherVersion}, sut.InstalledVersions)
+}
+
+func TestGoManager_BootstrapDirectory(t *testing.T) {
+	tempDir := t.TempDir()
+	sut := &GoManager{
+		RootDirectory: tempDir,
+		InstalledVersions: version.Collection{
+			version.Must(version.NewVersion("1.15.2")),
+			version.Must(version.NewVersion("1.9")),
+			version.Must(version.NewVersion("1.14.9")),
+		},
+	}
+
+	bootstrapDirectory, err := sut.bootstrapDirectory(version.Must(version.NewVersion("1.16")))
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(tempDir, "go1.15.2"), bootstrapDirectory)
+
+	bootstrapDirectory, err = sut.bootstrapDirectory(version.Must(version.NewVersion("1.15.2")))
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(tempDir, "go1.14.9"), bootstrapDirectory)
+
+	require.NoError(t, os.Setenv(BootstrapVariable, "/opt/go"))
+	t.Cleanup(func() {
+		_ = os.Unsetenv(BootstrapVariable)
+	})
+
+	bootstrapDirectory, err = sut.bootstrapDirectory(version.Must(version.NewVersion("1.16")))
+	assert.NoError(t, err)
+	assert.Equal(t, "/opt/go", bootstrapDirectory)
+
+	require.NoError(t, os.Unsetenv(BootstrapVariable))
+	sut.InstalledVersions = nil
+
+	_, err = sut.bootstrapDirectory(version.Must(version.NewVersion("1.16")))
+	assert.Error(t, err)
+}
+
+func TestGoManager_BootstrapDirectory_WithDevelopmentBuild(t *testing.T) {
+	develVersion := version.Must(version.NewVersion("1.22-devel.0123456789ab"))
+	tempDir := t.TempDir()
+	sut := &GoManager{
+		RootDirectory:     tempDir,
+		InstalledVersions: version.Collection{develVersion, version.Must(version.NewVersion("1.21.3"))},
+	}
+
+	bootstrapDirectory, err := sut.bootstrapDirectory(version.Must(version.NewVersion("1.22.1")))
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(tempDir, "go1.21.3"), bootstrapDirectory)
+
+	sut.InstalledVersions = version.Collection{develVersion}
+
+	bootstrapDirectory, err = sut.bootstrapDirectory(version.Must(version.NewVersion("1.22.1")))
+	assert.NoError(t, err)
+	assert.Equal(t, sut.SDKDirectory(develVersion), bootstrapDirectory)
+}
+
+func TestGoManager_BootstrapDirectory_WithOutdatedVersion(t *testing.T) {
+	tempDir := t.TempDir()
+	sut := &GoManager{
+		RootDirectory:     tempDir,
+		InstalledVersions: version.Collection{version.Must(version.NewVersion("1.19.13"))},
+	}
+
+	_, err := sut.bootstrapDirectory(version.Must(version.NewVersion("1.22.1")))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "requires Go 1.20.0 or later")
+
+	bootstrapDirectory, err := sut.bootstrapDirectory(version.Must(version.NewVersion("1.21.3")))
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(tempDir, "go1.19.13"), bootstrapDirectory)
+
+	setupInstallation(t, tempDir, true, "1.17.2")
+	require.NoError(t, os.Setenv(BootstrapVariable, filepath.Join(tempDir, "go1.17.2")))
+	t.Cleanup(func() {
+		_ = os.Unsetenv(BootstrapVariable)
+	})
+
+	_, err = sut.bootstrapDirectory(version.Must(version.NewVersion("1.20")))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), BootstrapVariable)
+
+	bootstrapDirectory, err = sut.bootstrapDirectory(version.Must(version.NewVersion("1.19.13")))
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(tempDir, "go1.17.2"), bootstrapDirectory)
+}
+
+func TestMinimumBootstrapVersion(t *testing.T) {
+	testCases := []struct {
+		version  string
+		expected string
+	}{
+		{version: "1.4.3", expected: ""},
+		{version: "1.15.2", expected: "1.4"},
+		{version: "1.20", expected: "1.17.13"},
+		{version: "1.21.3", expected: "1.17.13"},
+		{version: "1.22-devel.0123456789ab", expected: "1.20"},
+		{version: "1.23.1", expected: "1.20"},
+		{version: "1.24.0", expected: "1.22.6"},
+		{version: "1.27.1", expected: "1.24.6"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.version, func(t *testing.T) {
+			minimumVersion := minimumBootstrapVersion(version.Must(version.NewVersion(testCase.version)))
+			if testCase.expected == "" {
+				assert.Nil(t, minimumVersion)
+				return
+			}
+
+			require.NotNil(t, minimumVersion)
+			assert.True(t, minimumVersion.Equal(version.Must(version.NewVersion(testCase.expected))))
+		})
+	}
+}
+
+func TestBuildEnvironment(t *testing.T) {
+	environment := buildEnvironment("/opt/bootstrap", "/opt/go", []string{
+		"GOROOT=/usr/local/go",
+		"GOROOT_BOOTSTRAP=/usr/local/go",
+		"GOROOT_FINAL=/usr/local/go",
+		"HOME=/home/user",
+	})
+
+	assert.Equal(t, []string{"HOME=/home/user", "GOROOT_BOOTSTRAP=/opt/bootstrap", "GOROOT_FINAL=/opt/go"}, environment)
+}
+
+func TestLastLines(t *testing.T) {
+	assert.Equal(t, "", lastLines("", 2))
+	assert.Equal(t, "a\nb", lastLines("a\nb\n", 2))
+	assert.Equal(t, "b\nc", lastLines("a\nb\nc\n", 2))
+}
+
+func setupCachedSourceRelease(
+	t *testing.T,
+	manager *GoManager,
+	versionNumber *version.Version,
+	script string,
+) releases.ReleaseFile {
+	t.Helper()
+
+	versionName := fmt.Sprintf("go%s", toVersionName(versionNumber))
+	archivePath := filepath.Join(t.TempDir(), "source.zip")
+
+	archiveFile, err := os.Create(archivePath)
+	require.NoError(t, err)
+
+	archiveWriter := zip.NewWriter(archiveFile)
+	for name, content := range map[string]string{"go/VERSION": versionName, "go/src/make.bash": script} {
+		writer, err := archiveWriter.Create(name)
+		require.NoError(t, err)
+		_, err = writer.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, archiveWriter.Close())
+	require.NoError(t, archiveFile.Close())
+
+	content, err := ioutil.ReadFile(archivePath)
+	require.NoError(t, err)
+
+	file := releases.ReleaseFile{
+		Filename: fmt.Sprintf("%s.src.zip", versionName),
+		Version:  versionName,
+		Sha256:   fmt.Sprintf("%x", sha256.Sum256(content)),
+		Size:     int32(len(content)),
+		Kind:     releases.SourceFile,
+	}
+
+	cachedPath := manager.cachedArchivePath(file)
+	require.NoError(t, os.MkdirAll(filepath.Dir(cachedPath), 0700))
+	require.NoError(t, ioutil.WriteFile(cachedPath, content, 0600))
+	require.NoError(t, ioutil.WriteFile(cachedPath+verifiedSuffix, []byte(file.Sha256), 0600))
+
+	releases.ReleaseListCache[releases.IncludeAll] = append(
+		releases.ReleaseListCache[releases.IncludeAll],
+		&releases.Release{Version: versionName, Stable: true, Files: []releases.ReleaseFile{file}},
+	)
+	t.Cleanup(func() {
+		delete(releases.ReleaseListCache, releases.IncludeAll)
+	})
+
+	return file
+}
@@ -1,13 +1,16 @@
 package manager
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/go-version"
@@ -27,9 +30,8 @@ func TestGoManager_Install(t *testing.T) {
 	tempDir := t.TempDir()
 
 	sut, err := NewManager(&tasks.Task{
-		ErrorExitCode: 1,
-		Output:        os.Stdout,
-		Error:         os.Stderr,
+		Output: os.Stdout,
+		Error:  os.Stderr,
 	}, tempDir)
 	require.NoError(t, err)
 
@@ -46,9 +48,8 @@ func TestGoManager_Install_WithInvalidTarget(t *testing.T) {
 	validVersion := version.Must(version.NewVersion("1.15.2"))
 	tempDir := t.TempDir()
 	sut, err := NewManager(&tasks.Task{
-		ErrorExitCode: 1,
-		Output:        os.Stdout,
-		Error:         os.Stderr,
+		Output: os.Stdout,
+		Error:  os.Stderr,
 	}, tempDir)
 	require.NoError(t, err)
 
@@ -64,9 +65,8 @@ func TestGoManager_Install_WithHTTPError(t *testing.T) {
 	validVersion := version.Must(version.NewVersion("1.15.2"))
 	tempDir := t.TempDir()
 	sut, err := NewManager(&tasks.Task{
-		ErrorExitCode: 1,
-		Output:        os.Stdout,
-		Error:         os.Stderr,
+		Output: os.Stdout,
+		Error:  os.Stderr,
 	}, tempDir)
 	require.NoError(t, err)
 
@@ -79,6 +79,68 @@ func TestGoManager_Install_WithHTTPError(t *testing.T) {
 	assert.Error(t, sut.Install(validVersion, runtime.GOOS, runtime.GOARCH, releases.IncludeAll))
 }
 
+func TestGoManager_InstallAll(t *testing.T) {
+	firstVersion := version.Must(version.NewVersion("1.14.9"))
+	secondVersion := version.Must(version.NewVersion("1.15.2"))
+	missingVersion := version.Must(version.NewVersion("1.13.1"))
+	tempDir := t.TempDir()
+	output := &bytes.Buffer{}
+
+	sut, err := NewManager(&tasks.Task{
+		Output: output,
+		Error:  output,
+	}, tempDir)
+	require.NoError(t, err)
+
+	setupCachedRelease(t, sut, firstVersion)
+	setupCachedRelease(t, sut, secondVersion)
+
+	versionNumbers := version.Collection{secondVersion, firstVersion, secondVersion}
+	assert.NoError(t, sut.InstallAll(versionNumbers, runtime.GOOS, runtime.GOARCH, releases.IncludeAll, 4))
+	assert.DirExists(t, filepath.Join(tempDir, "go1.14.9"))
+	assert.DirExists(t, filepath.Join(tempDir, "go1.15.2"))
+	assert.Equal(t, version.Collection{firstVersion, secondVersion}, sut.InstalledVersions)
+	assert.Equal(t, 2, strings.Count(output.String(), "Installing "))
+
+	// The output of each installation has to be printed as complete lines, that tell to which installation they belong.
+	assert.Contains(t, output.String(), "[1.14.9] Installing 1.14.9")
+	assert.Contains(t, output.String(), "[1.14.9] Moving installation to final location... Done")
+	assert.Contains(t, output.String(), "[1.15.2] Moving installation to final location... Done")
+
+	require.NoError(t, sut.Uninstall(firstVersion))
+
+	versionNumbers = version.Collection{missingVersion, firstVersion, secondVersion}
+	err = sut.InstallAll(versionNumbers, runtime.GOOS, runtime.GOARCH, releases.IncludeAll, 2)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "1.13.1")
+	assert.Contains(t, err.Error(), "1.15.2")
+	assert.DirExists(t, filepath.Join(tempDir, "go1.14.9"))
+	assert.Equal(t, version.Collection{firstVersion, secondVersion}, sut.InstalledVersions)
+
+	// A single job has to keep going after a failure as well, and its output is not prefixed.
+	require.NoError(t, sut.Uninstall(firstVersion))
+	output.Reset()
+
+	err = sut.InstallAll(versionNumbers, runtime.GOOS, runtime.GOARCH, releases.IncludeAll, 1)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "1.13.1")
+	assert.DirExists(t, filepath.Join(tempDir, "go1.14.9"))
+	assert.Contains(t, output.String(), "Installing 1.14.9")
+	assert.NotContains(t, output.String(), "[1.14.9]")
+}
+
+func TestUniqueVersions(t *testing.T) {
+	firstVersion := version.Must(version.NewVersion("1.14.9"))
+	secondVersion := version.Must(version.NewVersion("1.15.2"))
+
+	assert.Empty(t, uniqueVersions(nil))
+	assert.Equal(
+		t,
+		version.Collection{secondVersion, firstVersion},
+		uniqueVersions(version.Collection{secondVersion, firstVersion, version.Must(version.NewVersion("1.15.2"))}),
+	)
+}
+
 func TestDownloadRelease(t *testing.T) {
 	t.Cleanup(func() {
 		httputil.Client = http.DefaultClient
@@ -87,34 +149,203 @@ func TestDownloadRelease(t *testing.T) {
 	file := releases.ReleaseFile{Filename: "go1.15.2.src.tar.gz"}
 	destinationFile := filepath.Join(t.TempDir(), "download.rel")
 
-	assert.NoError(t, downloadRelease(file, destinationFile))
-	assert.Error(t, downloadRelease(file, destinationFile))
+	assert.NoError(t, downloadRelease(file, destinationFile, nil))
+	assert.Error(t, downloadRelease(file, destinationFile, nil))
 
 	httputil.Client = httputil.StaticResponseClient(404, []byte("not found"), nil)
 	fileutil.TryRemove(destinationFile)
-	assert.Error(t, downloadRelease(file, destinationFile))
+	assert.Error(t, downloadRelease(file, destinationFile, nil))
 
 	httputil.Client = httputil.StaticResponseClient(0, nil, errors.New("failure"))
 	fileutil.TryRemove(destinationFile)
-	assert.Error(t, downloadRelease(file, destinationFile))
+	assert.Error(t, downloadRelease(file, destinationFile, nil))
 }
 
 func TestVerifyDownload(t *testing.T) {
 	file := releases.ReleaseFile{Filename: "go1.15.2.src.tar.gz", Sha256: "28bf9d0bcde251011caae230a4a05d917b172ea203f2a62f2c2f9533589d4b4d"}
 	destinationFile := filepath.Join(t.TempDir(), "download.rel")
 
-	require.NoError(t, downloadRelease(file, destinationFile))
-	assert.NoError(t, verifyDownload(file, destinationFile))
+	require.NoError(t, downloadRelease(file, destinationFile, nil))
+	assert.NoError(t, verifyDownload(file, destinationFile, nil, false))
 
 	fileutil.TryRemove(destinationFile)
 
-	assert.Error(t, verifyDownload(file, destinationFile))
+	assert.Error(t, verifyDownload(file, destinationFile, nil, false))
 
 	f, err := os.Create(destinationFile)
 	require.NoError(t, err)
 	_ = f.Close()
 
-	assert.Error(t, verifyDownload(file, destinationFile))
+	assert.Error(t, verifyDownload(file, destinationFile, nil, false))
+}
+
+func TestVerifyDownload_WithIndependentChecksum(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/hello.zip.sha256":
+			_, _ = w.Write([]byte(helloChecksum))
+		case "/tampered.zip.sha256":
+			_, _ = w.Write([]byte(strings.Repeat("0", len(helloChecksum))))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	releases.ChecksumBaseURL = server.URL
+	t.Cleanup(func() {
+		releases.ChecksumBaseURL = releases.OfficialChecksumBaseURL
+	})
+
+	destinationFile := filepath.Join(t.TempDir(), "download.zip")
+	require.NoError(t, ioutil.WriteFile(destinationFile, []byte("hello world"), 0600))
+
+	file := releases.ReleaseFile{Filename: "hello.zip", Sha256: helloChecksum}
+	assert.NoError(t, verifyDownload(file, destinationFile, nil, true))
+
+	file.Filename = "tampered.zip"
+	err := verifyDownload(file, destinationFile, nil, true)
+	assert.True(t, errors.Is(err, ErrChecksumMismatch))
+	assert.NoError(t, verifyDownload(file, destinationFile, nil, false))
+
+	file.Filename = "missing.zip"
+	err = verifyDownload(file, destinationFile, nil, true)
+	assert.True(t, errors.Is(err, errChecksumUnavailable))
+	assert.False(t, errors.Is(err, ErrChecksumMismatch))
+
+	file.Filename = "hello.zip"
+	pinnedChecksums := &PinnedChecksums{Path: ".gmn.sum", Checksums: map[string]string{"hello.zip": "other"}}
+	assert.Error(t, verifyDownload(file, destinationFile, pinnedChecksums, true))
+}
+
+func TestGoManager_Install_WithUnverifiedCachedArchive(t *testing.T) {
+	versionNumber := version.Must(version.NewVersion("1.15.2"))
+	tempDir := t.TempDir()
+
+	sut, err := NewManager(&tasks.Task{
+		Output: ioutil.Discard,
+		Error:  ioutil.Discard,
+	}, tempDir)
+	require.NoError(t, err)
+
+	file := setupCachedRelease(t, sut, versionNumber)
+	verifiedRecord := sut.cachedArchivePath(file) + verifiedSuffix
+	require.NoError(t, os.Remove(verifiedRecord))
+
+	independentChecksum := strings.Repeat("0", len(file.Sha256))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(independentChecksum))
+	}))
+	t.Cleanup(server.Close)
+
+	releases.ChecksumBaseURL = server.URL
+	t.Cleanup(func() {
+		releases.ChecksumBaseURL = releases.OfficialChecksumBaseURL
+		httputil.Client = http.DefaultClient
+	})
+
+	// The cached archive was never verified independently, so a tampered archive is caught before it is reused. Since the
+	// archive is downloaded again from the same server, the new download does not match either.
+	httputil.Client = httputil.StaticResponseClient(200, []byte(independentChecksum), nil)
+	assert.Error(t, sut.Install(versionNumber, runtime.GOOS, runtime.GOARCH, releases.IncludeAll))
+	assert.NoDirExists(t, filepath.Join(tempDir, "go1.15.2"))
+	assert.NoFileExists(t, sut.cachedArchivePath(file))
+
+	file = setupCachedRelease(t, sut, versionNumber)
+	require.NoError(t, os.Remove(verifiedRecord))
+	independentChecksum = file.Sha256
+	httputil.Client = server.Client()
+
+	assert.NoError(t, sut.Install(versionNumber, runtime.GOOS, runtime.GOARCH, releases.IncludeAll))
+	assert.DirExists(t, filepath.Join(tempDir, "go1.15.2"))
+	assert.True(t, isVerifiedIndependently(sut.cachedArchivePath(file), file.Sha256))
+}
+
+func TestGoManager_Install_WithUnreachableChecksumServer(t *testing.T) {
+	versionNumber := version.Must(version.NewVersion("1.15.2"))
+	tempDir := t.TempDir()
+	output := &bytes.Buffer{}
+
+	sut, err := NewManager(&tasks.Task{
+		Output: output,
+		Error:  output,
+	}, tempDir)
+	require.NoError(t, err)
+
+	file := setupCachedRelease(t, sut, versionNumber)
+	verifiedRecord := sut.cachedArchivePath(file) + verifiedSuffix
+	require.NoError(t, os.Remove(verifiedRecord))
+
+	releases.ChecksumBaseURL = "http://checksums.invalid/"
+	httputil.Client = httputil.StaticResponseClient(0, nil, errors.New("failure"))
+	t.Cleanup(func() {
+		releases.ChecksumBaseURL = releases.OfficialChecksumBaseURL
+		httputil.Client = http.DefaultClient
+	})
+
+	// The cached archive matches the checksum of the release list, so it is kept, but not recorded as verified independently.
+	assert.NoError(t, sut.Install(versionNumber, runtime.GOOS, runtime.GOARCH, releases.IncludeAll))
+	assert.DirExists(t, filepath.Join(tempDir, "go1.15.2"))
+	assert.FileExists(t, sut.cachedArchivePath(file))
+	assert.NoFileExists(t, verifiedRecord)
+	assert.Contains(t, output.String(), "could not retrieve independent checksum of "+file.Filename)
+}
+
+func TestGoManager_Install_WithoutChecksumServer(t *testing.T) {
+	versionNumber := version.Must(version.NewVersion("1.15.2"))
+	tempDir := t.TempDir()
+
+	sut, err := NewManager(&tasks.Task{
+		Output: ioutil.Discard,
+		Error:  ioutil.Discard,
+	}, tempDir)
+	require.NoError(t, err)
+
+	file := setupCachedRelease(t, sut, versionNumber)
+	verifiedRecord := sut.cachedArchivePath(file) + verifiedSuffix
+	require.NoError(t, os.Remove(verifiedRecord))
+
+	// While offline, no checksum server is configured and nothing may be retrieved from the network.
+	releases.ChecksumBaseURL = ""
+	httputil.Client = httputil.StaticResponseClient(0, nil, errors.New("failure"))
+	t.Cleanup(func() {
+		releases.ChecksumBaseURL = releases.OfficialChecksumBaseURL
+		httputil.Client = http.DefaultClient
+	})
+
+	assert.NoError(t, sut.Install(versionNumber, runtime.GOOS, runtime.GOARCH, releases.IncludeAll))
+	assert.DirExists(t, filepath.Join(tempDir, "go1.15.2"))
+	assert.FileExists(t, sut.cachedArchivePath(file))
+	assert.NoFileExists(t, verifiedRecord)
+}
+
+func TestGoManager_Install_WithPinnedChecksum(t *testing.T) {
+	t.Cleanup(func() {
+		httputil.Client = http.DefaultClient
+	})
+
+	versionNumber := version.Must(version.NewVersion("1.15.2"))
+	tempDir := t.TempDir()
+
+	sut, err := NewManager(&tasks.Task{
+		Output: ioutil.Discard,
+		Error:  ioutil.Discard,
+	}, tempDir)
+	require.NoError(t, err)
+
+	file := setupCachedRelease(t, sut, versionNumber)
+	sut.PinnedChecksums = &PinnedChecksums{Path: ".gmn.sum", Checksums: map[string]string{file.Filename: helloChecksum}}
+
+	// The cached archive does not match the pinned checksum, so it is dropped and has to be downloaded again.
+	httputil.Client = httputil.StaticResponseClient(0, nil, errors.New("failure"))
+	assert.Error(t, sut.Install(versionNumber, runtime.GOOS, runtime.GOARCH, releases.IncludeAll))
+	assert.NoDirExists(t, filepath.Join(tempDir, "go1.15.2"))
+	assert.NoFileExists(t, sut.cachedArchivePath(file))
+
+	file = setupCachedRelease(t, sut, versionNumber)
+	sut.PinnedChecksums.Checksums[file.Filename] = file.Sha256
+	assert.NoError(t, sut.Install(versionNumber, runtime.GOOS, runtime.GOARCH, releases.IncludeAll))
+	assert.DirExists(t, filepath.Join(tempDir, "go1.15.2"))
 }
 
 func TestExtractRelease(t *testing.T) {
@@ -122,16 +353,16 @@ func TestExtractRelease(t *testing.T) {
 	destinationFile := filepath.Join(t.TempDir(), "download.tar.gz")
 	destinationDirectory := filepath.Join(t.TempDir(), "extracted")
 
-	require.NoError(t, downloadRelease(file, destinationFile))
+	require.NoError(t, downloadRelease(file, destinationFile, nil))
 
-	assert.NoError(t, extractRelease(destinationFile, destinationDirectory))
-	assert.Error(t, extractRelease(destinationFile, destinationDirectory))
+	assert.NoError(t, extractRelease(destinationFile, destinationDirectory, nil))
+	assert.Error(t, extractRelease(destinationFile, destinationDirectory, nil))
 
 	fileutil.TryRemove(destinationFile)
-	assert.Error(t, extractRelease(destinationFile, destinationDirectory))
+	assert.Error(t, extractRelease(destinationFile, destinationDirectory, nil))
 
 	fileutil.TryRemove(destinationDirectory)
-	assert.Error(t, extractRelease(getTestFile(t, "invalid.zip"), destinationDirectory))
+	assert.Error(t, extractRelease(getTestFile(t, "invalid.zip"), destinationDirectory, nil))
 }
 
 func TestVerifyRelease(t *testing.T) {
@@ -163,3 +394,35 @@ func getTestFile(t *testing.T, fileName string) string {
 		fileName,
 	))
 }
+
+func TestGoManager_Install_ForOtherPlatform(t *testing.T) {
+	versionNumber := version.Must(version.NewVersion("1.15.2"))
+	tempDir := t.TempDir()
+
+	sut, err := NewManager(&tasks.Task{
+		Output: ioutil.Discard,
+		Error:  ioutil.Discard,
+	}, tempDir)
+	require.NoError(t, err)
+
+	setupCachedRelease(t, sut, versionNumber)
+	release := releases.ReleaseListCache[releases.IncludeAll][0]
+	release.Files[0].OS, release.Files[0].Arch = "plan9", "arm"
+
+	assert.NoError(t, sut.Install(versionNumber, "plan9", "arm", releases.IncludeAll))
+	assert.DirExists(t, filepath.Join(tempDir, "go1.15.2.plan9-arm"))
+	assert.FileExists(t, filepath.Join(tempDir, "go1.15.2.plan9-arm.manifest.json"))
+	assert.NoDirExists(t, filepath.Join(tempDir, "go1.15.2"))
+	assert.Empty(t, sut.InstalledVersions)
+	assert.Equal(t, []Installation{{
+		Version:   versionNumber,
+		OS:        "plan9",
+		Arch:      "arm",
+		Directory: filepath.Join(tempDir, "go1.15.2.plan9-arm"),
+	}}, sut.ForeignInstallations)
+
+	sut, err = NewManager(sut.task, tempDir)
+	require.NoError(t, err)
+	assert.Empty(t, sut.InstalledVersions)
+	assert.Len(t, sut.ForeignInstallations, 1)
+}
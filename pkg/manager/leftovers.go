@@ -0,0 +1,59 @@
+package manager
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jangraefen/go-man/internal/httputil"
+	"github.com/jangraefen/go-man/internal/lockutil"
+)
+
+const (
+	stagingDirectoryPrefix = "extracting-"
+
+	// Partial archives are kept for a while, so that an interrupted download can be resumed.
+	partialArchiveMaxAge = 24 * time.Hour
+)
+
+// removeLeftovers is a function that removes staging directories, temporary links and stale partial archives, that were
+// left behind by interrupted runs. Since these might as well belong to a run that is still in progress, it must only be
+// called while holding the lock on the root directory. As the cache directory might be shared with other root directories,
+// a partial archive is only removed if the lock on its directory can be acquired as well.
+func (m *GoManager) removeLeftovers() {
+	for _, leftover := range m.findLeftovers() {
+		if !strings.HasSuffix(leftover, httputil.PartialSuffix) {
+			_ = os.RemoveAll(leftover)
+			continue
+		}
+
+		archiveLock := lockutil.New(filepath.Join(filepath.Dir(leftover), lockFileName))
+		if err := archiveLock.Acquire(0); err == nil {
+			_ = os.Remove(leftover)
+			_ = archiveLock.Release()
+		}
+	}
+}
+
+func (m *GoManager) findLeftovers() []string {
+	var leftovers []string
+
+	fileInfos, _ := ioutil.ReadDir(m.RootDirectory)
+	for _, fileInfo := range fileInfos {
+		isStagingDirectory := fileInfo.IsDir() && strings.HasPrefix(fileInfo.Name(), stagingDirectoryPrefix)
+		if isStagingDirectory || fileInfo.Name() == selectedDirectoryName+temporaryLinkSuffix {
+			leftovers = append(leftovers, filepath.Join(m.RootDirectory, fileInfo.Name()))
+		}
+	}
+
+	partialArchives, _ := filepath.Glob(filepath.Join(m.archiveCacheDirectory(), "*", "*"+httputil.PartialSuffix))
+	for _, partialArchive := range partialArchives {
+		if fileInfo, err := os.Stat(partialArchive); err == nil && time.Since(fileInfo.ModTime()) > partialArchiveMaxAge {
+			leftovers = append(leftovers, partialArchive)
+		}
+	}
+
+	return leftovers
+}
@@ -0,0 +1,94 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jangraefen/go-man/internal/httputil"
+	"github.com/jangraefen/go-man/internal/lockutil"
+	"github.com/jangraefen/go-man/pkg/releases"
+	"github.com/jangraefen/go-man/pkg/tasks"
+)
+
+func TestGoManager_Lock_RemovesLeftovers(t *testing.T) {
+	tempDir := t.TempDir()
+	task := &tasks.Task{
+		Output: os.Stdout,
+		Error:  os.Stderr,
+	}
+
+	sut := &GoManager{RootDirectory: tempDir, CacheDirectory: filepath.Join(t.TempDir(), "custom-cache")}
+	stagingDirectory := filepath.Join(tempDir, stagingDirectoryPrefix+"go1.15.2")
+	setupInstallation(t, stagingDirectory, true, "1.15.2")
+	stalePartialArchive := setupCachedArchive(t, sut, "checksum1", "go1.15.2.linux-amd64.tar.gz"+httputil.PartialSuffix)
+	freshPartialArchive := setupCachedArchive(t, sut, "checksum2", "go1.15.3.linux-amd64.tar.gz"+httputil.PartialSuffix)
+	lockedPartialArchive := setupCachedArchive(t, sut, "checksum3", "go1.15.4.linux-amd64.tar.gz"+httputil.PartialSuffix)
+
+	staleTime := time.Now().Add(-2 * partialArchiveMaxAge)
+	require.NoError(t, os.Chtimes(stalePartialArchive, staleTime, staleTime))
+	require.NoError(t, os.Chtimes(lockedPartialArchive, staleTime, staleTime))
+
+	// Another root directory, that shares the cache directory, is still downloading into this partial archive.
+	otherRoot := lockutil.New(filepath.Join(filepath.Dir(lockedPartialArchive), lockFileName))
+	require.NoError(t, otherRoot.Acquire(time.Second))
+	t.Cleanup(func() {
+		_ = otherRoot.Release()
+	})
+
+	// Creating a manager must never remove anything, since it is used by read-only commands as well.
+	manager, err := NewManager(task, tempDir)
+	require.NoError(t, err)
+	assert.Empty(t, manager.InstalledVersions)
+	assert.DirExists(t, stagingDirectory)
+
+	manager.CacheDirectory = sut.CacheDirectory
+	manager.LockTimeout = 100 * time.Millisecond
+
+	otherProcess := lockutil.New(filepath.Join(tempDir, lockFileName))
+	require.NoError(t, otherProcess.Acquire(time.Second))
+
+	_, err = manager.lock()
+	assert.Error(t, err)
+	assert.DirExists(t, stagingDirectory)
+	assert.FileExists(t, stalePartialArchive)
+
+	require.NoError(t, otherProcess.Release())
+
+	unlock, err := manager.lock()
+	require.NoError(t, err)
+	unlock()
+
+	assert.Empty(t, manager.InstalledVersions)
+	assert.NoDirExists(t, stagingDirectory)
+	assert.NoFileExists(t, stalePartialArchive)
+	assert.FileExists(t, freshPartialArchive)
+	assert.FileExists(t, lockedPartialArchive)
+}
+
+func TestGoManager_Install_WithLeftoverStagingDirectory(t *testing.T) {
+	validVersion := version.Must(version.NewVersion("1.15.2"))
+	tempDir := t.TempDir()
+
+	sut := &GoManager{
+		RootDirectory: tempDir,
+		task: &tasks.Task{
+			Output: os.Stdout,
+			Error:  os.Stderr,
+		},
+	}
+	setupCachedRelease(t, sut, validVersion)
+
+	stagingDirectory := filepath.Join(tempDir, stagingDirectoryPrefix+"go1.15.2")
+	setupInstallation(t, stagingDirectory, true, "1.14.9")
+
+	assert.NoError(t, sut.Install(validVersion, runtime.GOOS, runtime.GOARCH, releases.IncludeAll))
+	assert.FileExists(t, filepath.Join(sut.SDKDirectory(validVersion), "VERSION"))
+	assert.NoDirExists(t, stagingDirectory)
+}
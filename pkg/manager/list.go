@@ -0,0 +1,144 @@
+package manager
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/go-version"
+
+	"github.com/jangraefen/go-man/pkg/releases"
+)
+
+// ListOptions is a struct that describes which versions of the Go SDK are listed by List.
+type ListOptions struct {
+	// If set, only installed versions are listed and the release source is not consulted at all.
+	InstalledOnly bool
+	// If set, only released versions that are not installed yet are listed.
+	RemoteOnly bool
+	// The release type that limits the listed releases.
+	ReleaseType releases.ReleaseType
+	// The operating system that listed releases have to provide a file for and listed installations have to be built for.
+	OS string
+	// The processor architecture that listed releases have to provide a file for and listed installations have to be built
+	// for.
+	Arch string
+	// The kind of file that listed releases have to provide.
+	Kind releases.FileKind
+}
+
+// ListedVersion is a struct that describes a single version of the Go SDK, as it is listed by List.
+type ListedVersion struct {
+	// The version of the Go SDK.
+	Version *version.Version
+	// The name of the version, like it is accepted by Select or Uninstall.
+	Name string
+	// Whether the version is offered by the release source.
+	Released bool
+	// Whether the version is installed for the requested platform.
+	Installed bool
+	// Whether the version is the selected one.
+	Selected bool
+	// The directory of the installation. Empty, if the version is not installed.
+	Directory string
+	// The files of the release, that match the requested platform and kind. Empty, if the version is not released.
+	Files []releases.ReleaseFile
+}
+
+// ReleaseLine is a struct that groups all listed versions, that share the same minor version, like "1.15".
+type ReleaseLine struct {
+	// The name of the release line.
+	Name string
+	// The versions of the release line, from the highest to the lowest.
+	Versions []ListedVersion
+}
+
+// List is a function that lists the versions of the Go SDK, that are either offered by the release source or installed.
+// Each version is marked as installed and selected, if it is. The listed versions are sorted from the highest to the lowest.
+func (m *GoManager) List(options ListOptions) ([]ListedVersion, error) {
+	var listed []ListedVersion
+
+	for _, installation := range m.Installations() {
+		if installation.OS != options.OS || installation.Arch != options.Arch {
+			continue
+		}
+
+		listed = append(listed, ListedVersion{
+			Version:   installation.Version,
+			Name:      toVersionName(installation.Version),
+			Installed: true,
+			Selected:  installation.Native() && installation.Version.Equal(m.SelectedVersion),
+			Directory: installation.Directory,
+		})
+	}
+
+	if !options.InstalledOnly {
+		releaseList, err := releases.ListAll(options.ReleaseType)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, release := range releaseList {
+			files := release.FindFiles(options.OS, options.Arch, options.Kind)
+			if len(files) == 0 {
+				continue
+			}
+
+			index := listedIndex(listed, release.GetVersionNumber())
+			if index < 0 {
+				listed = append(listed, ListedVersion{
+					Version: release.GetVersionNumber(),
+					Name:    toVersionName(release.GetVersionNumber()),
+				})
+				index = len(listed) - 1
+			}
+
+			listed[index].Released = true
+			listed[index].Files = files
+		}
+	}
+
+	if options.RemoteOnly {
+		var remote []ListedVersion
+		for _, listedVersion := range listed {
+			if listedVersion.Released && !listedVersion.Installed {
+				remote = append(remote, listedVersion)
+			}
+		}
+
+		listed = remote
+	}
+
+	sort.SliceStable(listed, func(i, j int) bool {
+		return listed[i].Version.GreaterThan(listed[j].Version)
+	})
+
+	return listed, nil
+}
+
+// GroupByReleaseLine is a function that groups listed versions by their release line, keeping their order.
+func GroupByReleaseLine(listed []ListedVersion) []ReleaseLine {
+	var lines []ReleaseLine
+
+	for _, listedVersion := range listed {
+		segments := listedVersion.Version.Segments()
+		name := fmt.Sprintf("%d.%d", segments[0], segments[1])
+
+		if len(lines) == 0 || lines[len(lines)-1].Name != name {
+			lines = append(lines, ReleaseLine{Name: name})
+		}
+
+		lines[len(lines)-1].Versions = append(lines[len(lines)-1].Versions, listedVersion)
+	}
+
+	return lines
+}
+
+func listedIndex(listed []ListedVersion, versionNumber *version.Version) int {
+	for index, listedVersion := range listed {
+		if listedVersion.Version.Equal(versionNumber) {
+			return index
+		}
+	}
+
+	return -1
+}
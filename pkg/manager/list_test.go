@@ -0,0 +1,100 @@
+package manager
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jangraefen/go-man/pkg/releases"
+)
+
+func TestGoManager_List(t *testing.T) {
+	t.Cleanup(func() {
+		delete(releases.ReleaseListCache, releases.IncludeAll)
+	})
+
+	installedVersion := version.Must(version.NewVersion("1.15.2"))
+	develVersion := version.Must(version.NewVersion("1.16-devel.0123456789ab"))
+	nativeFile := releases.ReleaseFile{
+		Filename: "go1.15.2.zip",
+		OS:       runtime.GOOS,
+		Arch:     runtime.GOARCH,
+		Kind:     releases.ArchiveFile,
+	}
+	sourceFile := releases.ReleaseFile{Filename: "go1.14.9.src.tar.gz", Kind: releases.SourceFile}
+
+	releases.ReleaseListCache[releases.IncludeAll] = releases.Collection{
+		{Version: "go1.14.9", Files: []releases.ReleaseFile{sourceFile}},
+		{Version: "go1.15.2", Stable: true, Files: []releases.ReleaseFile{nativeFile}},
+		{Version: "go1.15.3", Stable: true, Files: []releases.ReleaseFile{
+			{Filename: "go1.15.3.plan9-arm.zip", OS: "plan9", Arch: "arm", Kind: releases.ArchiveFile},
+		}},
+	}
+
+	sut := &GoManager{
+		RootDirectory:     t.TempDir(),
+		InstalledVersions: version.Collection{develVersion, installedVersion},
+		SelectedVersion:   installedVersion,
+	}
+
+	options := ListOptions{ReleaseType: releases.IncludeAll, OS: runtime.GOOS, Arch: runtime.GOARCH, Kind: releases.ArchiveFile}
+
+	listed, err := sut.List(options)
+	require.NoError(t, err)
+	require.Len(t, listed, 2)
+	assert.Equal(t, "1.16devel.0123456789ab", listed[0].Name)
+	assert.False(t, listed[0].Released)
+	assert.Equal(t, "1.15.2", listed[1].Name)
+	assert.True(t, listed[1].Released)
+	assert.True(t, listed[1].Installed)
+	assert.True(t, listed[1].Selected)
+	assert.Equal(t, sut.SDKDirectory(installedVersion), listed[1].Directory)
+	assert.Equal(t, []releases.ReleaseFile{nativeFile}, listed[1].Files)
+
+	options.Kind = releases.SourceFile
+	listed, err = sut.List(options)
+	require.NoError(t, err)
+	require.Len(t, listed, 3)
+	assert.False(t, listed[1].Released)
+	assert.Equal(t, "1.14.9", listed[2].Name)
+	assert.True(t, listed[2].Released)
+	assert.False(t, listed[2].Installed)
+
+	options.RemoteOnly = true
+	listed, err = sut.List(options)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, "1.14.9", listed[0].Name)
+
+	// Installed versions are listed without consulting the release source, so no release list is needed.
+	delete(releases.ReleaseListCache, releases.IncludeAll)
+	sut.ForeignInstallations = []Installation{
+		{Version: installedVersion, OS: "plan9", Arch: "arm", Directory: "go1.15.2.plan9-arm"},
+	}
+
+	listed, err = sut.List(ListOptions{InstalledOnly: true, OS: "plan9", Arch: "arm"})
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, "go1.15.2.plan9-arm", listed[0].Directory)
+	assert.False(t, listed[0].Selected)
+}
+
+func TestGroupByReleaseLine(t *testing.T) {
+	listed := []ListedVersion{
+		{Version: version.Must(version.NewVersion("1.16-devel.0123456789ab"))},
+		{Version: version.Must(version.NewVersion("1.15.3"))},
+		{Version: version.Must(version.NewVersion("1.15"))},
+		{Version: version.Must(version.NewVersion("1.14.9"))},
+	}
+
+	lines := GroupByReleaseLine(listed)
+	require.Len(t, lines, 3)
+	assert.Equal(t, ReleaseLine{Name: "1.16", Versions: listed[:1]}, lines[0])
+	assert.Equal(t, ReleaseLine{Name: "1.15", Versions: listed[1:3]}, lines[1])
+	assert.Equal(t, ReleaseLine{Name: "1.14", Versions: listed[3:]}, lines[2])
+
+	assert.Empty(t, GroupByReleaseLine(nil))
+}
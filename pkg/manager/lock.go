@@ -0,0 +1,98 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jangraefen/go-man/internal/lockutil"
+	"github.com/jangraefen/go-man/pkg/tasks"
+)
+
+const (
+	// DefaultLockTimeout is the duration that is waited for another gmn process to finish, before giving up.
+	DefaultLockTimeout = 5 * time.Minute
+
+	lockFileName = ".gmn.lock"
+)
+
+// lock is a function that acquires the lock on the root directory, which keeps other gmn processes from modifying the root
+// directory at the same time. The lock is reentrant, so operations that are composed of other operations may acquire it as
+// well. Since another gmn process might have modified the root directory before the lock was acquired, its state is detected
+// again whenever the lock is acquired by this process for the first time. Leftovers of interrupted runs are removed at that
+// point as well, since no other run can be in progress. The returned function has to be called to release the lock again.
+func (m *GoManager) lock() (func(), error) {
+	rootLock := m.getRootLock()
+	reentered := rootLock.Held()
+	timeout := m.lockTimeout()
+
+	err := rootLock.Acquire(0)
+	if errors.Is(err, lockutil.ErrLocked) {
+		m.task.Printf("Waiting for another gmn process to finish")
+		err = rootLock.Acquire(timeout)
+	}
+	if errors.Is(err, lockutil.ErrLocked) {
+		return nil, fmt.Errorf("another gmn is running in %s and did not finish within %s: %w", m.RootDirectory, timeout, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !reentered {
+		m.removeLeftovers()
+		if err := m.refresh(); err != nil {
+			_ = rootLock.Release()
+			return nil, err
+		}
+	}
+
+	return func() { _ = rootLock.Release() }, nil
+}
+
+// lockArchive is a function that acquires the lock on the directory of a cached archive. Since the cache directory might be
+// shared by multiple root directories, the lock on the root directory does not keep other gmn processes from downloading the
+// same archive into the same partial file. The returned function has to be called to release the lock again.
+func (m *GoManager) lockArchive(task *tasks.Task, archivePath string) (func(), error) {
+	archiveDirectory := filepath.Dir(archivePath)
+	if err := os.MkdirAll(archiveDirectory, 0755); err != nil {
+		return nil, err
+	}
+
+	archiveLock := lockutil.New(filepath.Join(archiveDirectory, lockFileName))
+	timeout := m.lockTimeout()
+
+	err := archiveLock.Acquire(0)
+	if errors.Is(err, lockutil.ErrLocked) {
+		task.Printf("Waiting for another gmn process to finish downloading %s", filepath.Base(archivePath))
+		err = archiveLock.Acquire(timeout)
+	}
+	if errors.Is(err, lockutil.ErrLocked) {
+		return nil, fmt.Errorf("another gmn is downloading %s and did not finish within %s: %w", archivePath, timeout, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return func() { _ = archiveLock.Release() }, nil
+}
+
+func (m *GoManager) lockTimeout() time.Duration {
+	if m.LockTimeout <= 0 {
+		return DefaultLockTimeout
+	}
+
+	return m.LockTimeout
+}
+
+func (m *GoManager) getRootLock() *lockutil.Lock {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.rootLock == nil {
+		m.rootLock = lockutil.New(filepath.Join(m.RootDirectory, lockFileName))
+	}
+
+	return m.rootLock
+}
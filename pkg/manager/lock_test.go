@@ -0,0 +1,114 @@
+package manager
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jangraefen/go-man/internal/lockutil"
+	"github.com/jangraefen/go-man/pkg/releases"
+	"github.com/jangraefen/go-man/pkg/tasks"
+)
+
+func TestGoManager_Lock(t *testing.T) {
+	installedVersion := version.Must(version.NewVersion("1.15.2"))
+	tempDir := t.TempDir()
+	setupInstallation(t, tempDir, true, "1.15.2")
+
+	sut, err := NewManager(&tasks.Task{
+		Output: os.Stdout,
+		Error:  os.Stderr,
+	}, tempDir)
+	require.NoError(t, err)
+	sut.LockTimeout = 100 * time.Millisecond
+
+	otherProcess := lockutil.New(filepath.Join(tempDir, lockFileName))
+	require.NoError(t, otherProcess.Acquire(time.Second))
+
+	err = sut.Select(installedVersion)
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, lockutil.ErrLocked))
+	assert.Contains(t, err.Error(), "another gmn is running")
+	assert.Nil(t, sut.SelectedVersion)
+
+	err = sut.Uninstall(installedVersion)
+	assert.True(t, errors.Is(err, lockutil.ErrLocked))
+	assert.DirExists(t, sut.SDKDirectory(installedVersion))
+
+	require.NoError(t, otherProcess.Release())
+
+	assert.NoError(t, sut.Select(installedVersion))
+	assert.NoError(t, sut.UninstallAll())
+	assert.NoDirExists(t, sut.SDKDirectory(installedVersion))
+	assert.NoError(t, otherProcess.Acquire(0))
+	assert.NoError(t, otherProcess.Release())
+}
+
+func TestGoManager_Lock_RefreshesState(t *testing.T) {
+	installedVersion := version.Must(version.NewVersion("1.15.2"))
+	otherVersion := version.Must(version.NewVersion("1.14.9"))
+	tempDir := t.TempDir()
+	setupInstallation(t, tempDir, true, "1.15.2")
+
+	sut, err := NewManager(&tasks.Task{
+		Output: os.Stdout,
+		Error:  os.Stderr,
+	}, tempDir)
+	require.NoError(t, err)
+
+	// Another process installs a version and removes one, after this manager detected the state of the root directory.
+	setupInstallation(t, tempDir, true, "1.14.9")
+	require.NoError(t, os.RemoveAll(sut.SDKDirectory(installedVersion)))
+
+	unlock, err := sut.lock()
+	require.NoError(t, err)
+	assert.Equal(t, version.Collection{otherVersion}, sut.InstalledVersions)
+
+	// A reentered lock must not detect the state again, since the operation holding the lock relies on it.
+	sut.InstalledVersions = version.Collection{installedVersion}
+	reentrantUnlock, err := sut.lock()
+	require.NoError(t, err)
+	assert.Equal(t, version.Collection{installedVersion}, sut.InstalledVersions)
+
+	reentrantUnlock()
+	unlock()
+}
+
+func TestGoManager_Install_WithLockedArchive(t *testing.T) {
+	versionNumber := version.Must(version.NewVersion("1.15.2"))
+	tempDir := t.TempDir()
+
+	sut, err := NewManager(&tasks.Task{
+		Output: ioutil.Discard,
+		Error:  ioutil.Discard,
+	}, tempDir)
+	require.NoError(t, err)
+	sut.LockTimeout = 100 * time.Millisecond
+
+	// The cache directory might be shared with another root directory, whose lock does not cover this one.
+	file := setupCachedRelease(t, sut, versionNumber)
+	otherRoot := lockutil.New(filepath.Join(filepath.Dir(sut.cachedArchivePath(file)), lockFileName))
+	require.NoError(t, otherRoot.Acquire(time.Second))
+
+	err = sut.Install(versionNumber, runtime.GOOS, runtime.GOARCH, releases.IncludeAll)
+	assert.True(t, errors.Is(err, lockutil.ErrLocked))
+	assert.Contains(t, err.Error(), "another gmn is downloading")
+	assert.NoDirExists(t, sut.SDKDirectory(versionNumber))
+
+	require.NoError(t, otherRoot.Release())
+
+	assert.NoError(t, sut.Install(versionNumber, runtime.GOOS, runtime.GOARCH, releases.IncludeAll))
+	assert.DirExists(t, sut.SDKDirectory(versionNumber))
+
+	archives, err := sut.CachedArchives()
+	require.NoError(t, err)
+	assert.Len(t, archives, 1)
+}
@@ -1,17 +1,24 @@
 package manager
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/hashicorp/go-version"
 
+	"github.com/jangraefen/go-man/internal/lockutil"
 	"github.com/jangraefen/go-man/pkg/tasks"
 )
 
 const (
 	selectedDirectoryName = "go-default"
+	cacheDirectoryName    = "cache"
 )
 
 // The GoManager is responsible for managing Go SDK installations.
@@ -20,46 +27,189 @@ const (
 type GoManager struct {
 	// The root directory stores the installed SDKs as well as any configuration files.
 	RootDirectory string
-	// The collection of all currently installed versions of the Go SDK.
+	// The collection of all currently installed versions of the Go SDK, that were built for the current platform.
 	InstalledVersions version.Collection
+	// The installations of the Go SDK, that were built for other platforms. These cannot be used on the current platform, but
+	// are kept side by side with the native installations, for example to package them.
+	ForeignInstallations []Installation
 	// The currently selected version. The selected version is the release that is synced to the "selected" directory. Might
 	// be nil, if no version is currently selected.
 	SelectedVersion *version.Version
+	// The cache directory stores downloaded archives, so they can be reused. It may be shared by multiple root directories. If
+	// empty, a directory inside the root directory is used.
+	CacheDirectory string
+	// The duration that is waited for another gmn process to finish modifying the root directory. If zero,
+	// DefaultLockTimeout is used.
+	LockTimeout time.Duration
+	// The checksums that release files have to match, in addition to the checksums of the release list. Might be nil, if no
+	// checksums are pinned.
+	PinnedChecksums *PinnedChecksums
 
-	task *tasks.Task
+	task     *tasks.Task
+	mutex    sync.Mutex
+	rootLock *lockutil.Lock
+}
+
+// Installation is a struct that describes a single installation of the Go SDK inside the root directory.
+type Installation struct {
+	// The installed version of the Go SDK.
+	Version *version.Version
+	// The operating system that the installation was built for.
+	OS string
+	// The processor architecture that the installation was built for.
+	Arch string
+	// The directory that the installation is located at.
+	Directory string
+}
+
+// Native is a function that checks if the installation was built for the current platform.
+func (i Installation) Native() bool {
+	return isNativePlatform(i.OS, i.Arch)
 }
 
 // NewManager is a constructor for the GoManager struct.
 // It reads through the given root directory and detects the current state and initializes the GoManager instance
 // accordingly.
 func NewManager(task *tasks.Task, rootDirectory string) (*GoManager, error) {
+	installedVersions, foreignInstallations, selectedVersion, err := detectInstallations(rootDirectory)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GoManager{
+		RootDirectory:        rootDirectory,
+		InstalledVersions:    installedVersions,
+		ForeignInstallations: foreignInstallations,
+		SelectedVersion:      selectedVersion,
+		CacheDirectory:       filepath.Join(rootDirectory, cacheDirectoryName),
+		task:                 task,
+	}, nil
+}
+
+// SDKDirectory is a function that returns the directory an installation of the given Go SDK version is located at.
+// The directory is returned regardless of the version actually being installed.
+func (m *GoManager) SDKDirectory(versionNumber *version.Version) string {
+	return filepath.Join(m.RootDirectory, fmt.Sprintf("go%s", toVersionName(versionNumber)))
+}
+
+// SDKDirectoryForPlatform is a function that returns the directory an installation of the given Go SDK version for the given
+// platform is located at. Installations for the current platform are located at SDKDirectory, while the directories of
+// installations for other platforms are qualified by their platform, like "go1.15.2.windows-amd64".
+func (m *GoManager) SDKDirectoryForPlatform(versionNumber *version.Version, operatingSystem, arch string) string {
+	if isNativePlatform(operatingSystem, arch) {
+		return m.SDKDirectory(versionNumber)
+	}
+
+	return fmt.Sprintf("%s.%s-%s", m.SDKDirectory(versionNumber), operatingSystem, arch)
+}
+
+// Installations is a function that returns all installations of the Go SDK, including those for other platforms.
+// The installations are sorted by their version and platform.
+func (m *GoManager) Installations() []Installation {
+	installations := make([]Installation, 0, len(m.InstalledVersions)+len(m.ForeignInstallations))
+	for _, installedVersion := range m.InstalledVersions {
+		installations = append(installations, Installation{
+			Version:   installedVersion,
+			OS:        runtime.GOOS,
+			Arch:      runtime.GOARCH,
+			Directory: m.SDKDirectory(installedVersion),
+		})
+	}
+	installations = append(installations, m.ForeignInstallations...)
+
+	sort.SliceStable(installations, func(i, j int) bool {
+		if !installations[i].Version.Equal(installations[j].Version) {
+			return installations[i].Version.LessThan(installations[j].Version)
+		}
+
+		return installations[i].OS+"-"+installations[i].Arch < installations[j].OS+"-"+installations[j].Arch
+	})
+
+	return installations
+}
+
+// SelectedDirectory is a function that returns the directory that points to the currently selected installation.
+// The directory is returned regardless of a version actually being selected.
+func (m *GoManager) SelectedDirectory() string {
+	return filepath.Join(m.RootDirectory, selectedDirectoryName)
+}
+
+// FindInstalled is a function that returns the highest installed version, that satisfies the given version constraints.
+// If no installed version satisfies the constraints, the boolean return value will be set to false.
+func (m *GoManager) FindInstalled(constraints version.Constraints) (*version.Version, bool) {
+	var found *version.Version
+
+	for _, installedVersion := range m.InstalledVersions {
+		if constraints.Check(installedVersion) && (found == nil || installedVersion.GreaterThan(found)) {
+			found = installedVersion
+		}
+	}
+
+	return found, found != nil
+}
+
+// refresh is a function that detects the state of the root directory again, since another gmn process might have modified
+// it after the GoManager was created.
+func (m *GoManager) refresh() error {
+	installedVersions, foreignInstallations, selectedVersion, err := detectInstallations(m.RootDirectory)
+	if err != nil {
+		return err
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.InstalledVersions = installedVersions
+	m.ForeignInstallations = foreignInstallations
+	m.SelectedVersion = selectedVersion
+	return nil
+}
+
+// detectInstallations is a function that reads through the given root directory and detects the installed versions, the
+// installations for other platforms and the selected version.
+func detectInstallations(rootDirectory string) (version.Collection, []Installation, *version.Version, error) {
 	var selectedVersion *version.Version
 	var installedVersions version.Collection
+	var foreignInstallations []Installation
 
 	fileInfos, err := ioutil.ReadDir(rootDirectory)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
 	for _, fileInfo := range fileInfos {
+		if fileInfo.Name() == selectedDirectoryName+temporaryLinkSuffix {
+			continue
+		}
+
 		if fileInfo.IsDir() || fileInfo.Mode()&os.ModeSymlink != 0 {
 			detectedVersion, err := detectGoVersion(filepath.Join(rootDirectory, fileInfo.Name()))
 			if err != nil {
 				continue
 			}
 
-			if fileInfo.Name() == selectedDirectoryName {
+			sdkDirectory := filepath.Join(rootDirectory, fileInfo.Name())
+			operatingSystem, arch := detectGoPlatform(sdkDirectory, detectedVersion)
+
+			switch {
+			case fileInfo.Name() == selectedDirectoryName:
 				selectedVersion = detectedVersion
-			} else {
+			case isNativePlatform(operatingSystem, arch):
 				installedVersions = append(installedVersions, detectedVersion)
+			default:
+				foreignInstallations = append(foreignInstallations, Installation{
+					Version:   detectedVersion,
+					OS:        operatingSystem,
+					Arch:      arch,
+					Directory: sdkDirectory,
+				})
 			}
 		}
 	}
 
-	return &GoManager{
-		RootDirectory:     rootDirectory,
-		InstalledVersions: installedVersions,
-		SelectedVersion:   selectedVersion,
-		task:              task,
-	}, nil
+	return installedVersions, foreignInstallations, selectedVersion, nil
+}
+
+func isNativePlatform(operatingSystem, arch string) bool {
+	return operatingSystem == runtime.GOOS && arch == runtime.GOARCH
 }
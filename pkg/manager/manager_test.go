@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 
 	"github.com/hashicorp/go-version"
@@ -15,7 +16,7 @@ import (
 )
 
 func TestNewManager(t *testing.T) {
-	task := &tasks.Task{ErrorExitCode: 1, Output: os.Stdout, Error: os.Stderr}
+	task := &tasks.Task{Output: os.Stdout, Error: os.Stderr}
 	rootDirectory := t.TempDir()
 
 	validVersion := version.Must(version.NewVersion("1.15.2"))
@@ -68,3 +69,98 @@ func setupInstallation(t *testing.T, rootDirectory string, valid bool, goVersion
 	require.NoError(t, os.MkdirAll(sdkPath, 0700), "Could not create installation directory", sdkPath)
 	require.NoError(t, ioutil.WriteFile(versionPath, []byte(versionContent), 0600))
 }
+
+func TestGoManager_FindInstalled(t *testing.T) {
+	sut := &GoManager{
+		RootDirectory: t.TempDir(),
+		InstalledVersions: version.Collection{
+			version.Must(version.NewVersion("1.15.10")),
+			version.Must(version.NewVersion("1.16rc1")),
+			version.Must(version.NewVersion("1.15.2")),
+			version.Must(version.NewVersion("1.14.9")),
+		},
+	}
+
+	constraints, err := version.NewConstraint(">= 1.15, < 1.17")
+	require.NoError(t, err)
+
+	found, exists := sut.FindInstalled(constraints)
+	assert.True(t, exists)
+	assert.Equal(t, "1.15.10", found.String())
+
+	constraints, err = version.NewConstraint("< 1.14")
+	require.NoError(t, err)
+
+	found, exists = sut.FindInstalled(constraints)
+	assert.False(t, exists)
+	assert.Nil(t, found)
+}
+
+func TestNewManager_WithForeignInstallations(t *testing.T) {
+	task := &tasks.Task{Output: os.Stdout, Error: os.Stderr}
+	rootDirectory := t.TempDir()
+
+	setupInstallation(t, rootDirectory, true, "1.15.2")
+	setupInstallation(t, rootDirectory, true, "1.14.9")
+	require.NoError(t, os.Rename(filepath.Join(rootDirectory, "go1.15.2"), filepath.Join(rootDirectory, "go1.15.2.plan9-arm")))
+	require.NoError(t, os.MkdirAll(filepath.Join(rootDirectory, "go1.14.9", "pkg", "tool", "plan9_386"), 0700))
+	setupInstallation(t, rootDirectory, true, "1.15.2")
+
+	manager, err := NewManager(task, rootDirectory)
+	require.NoError(t, err)
+	assert.Equal(t, version.Collection{version.Must(version.NewVersion("1.15.2"))}, manager.InstalledVersions)
+	assert.ElementsMatch(t, []Installation{
+		{
+			Version:   version.Must(version.NewVersion("1.15.2")),
+			OS:        "plan9",
+			Arch:      "arm",
+			Directory: filepath.Join(rootDirectory, "go1.15.2.plan9-arm"),
+		},
+		{
+			Version:   version.Must(version.NewVersion("1.14.9")),
+			OS:        "plan9",
+			Arch:      "386",
+			Directory: filepath.Join(rootDirectory, "go1.14.9"),
+		},
+	}, manager.ForeignInstallations)
+}
+
+func TestGoManager_SDKDirectoryForPlatform(t *testing.T) {
+	versionNumber := version.Must(version.NewVersion("1.16"))
+	sut := &GoManager{RootDirectory: t.TempDir()}
+
+	assert.Equal(t, sut.SDKDirectory(versionNumber), sut.SDKDirectoryForPlatform(versionNumber, runtime.GOOS, runtime.GOARCH))
+	assert.Equal(
+		t,
+		filepath.Join(sut.RootDirectory, "go1.16.plan9-arm"),
+		sut.SDKDirectoryForPlatform(versionNumber, "plan9", "arm"),
+	)
+}
+
+func TestGoManager_Installations(t *testing.T) {
+	newerVersion := version.Must(version.NewVersion("1.15.2"))
+	olderVersion := version.Must(version.NewVersion("1.14.9"))
+
+	sut := &GoManager{
+		RootDirectory:     t.TempDir(),
+		InstalledVersions: version.Collection{newerVersion, olderVersion},
+		ForeignInstallations: []Installation{
+			{Version: newerVersion, OS: "zos", Arch: "s390x", Directory: "go1.15.2.zos-s390x"},
+			{Version: newerVersion, OS: "aix", Arch: "ppc64", Directory: "go1.15.2.aix-ppc64"},
+		},
+	}
+
+	installations := sut.Installations()
+	require.Len(t, installations, 4)
+	assert.Equal(t, Installation{
+		Version:   olderVersion,
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		Directory: sut.SDKDirectory(olderVersion),
+	}, installations[0])
+	assert.True(t, installations[0].Native())
+	assert.Equal(t, "aix", installations[1].OS)
+	assert.False(t, installations[1].Native())
+	assert.True(t, installations[2].Native())
+	assert.Equal(t, "zos", installations[3].OS)
+}
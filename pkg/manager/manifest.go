@@ -0,0 +1,190 @@
+package manager
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/go-version"
+
+	"github.com/jangraefen/go-man/pkg/releases"
+)
+
+const (
+	manifestSuffix = ".manifest.json"
+)
+
+// Manifest is a struct that describes the state of an installation right after it was installed.
+// It is stored next to the installation directory and allows to detect later modifications of the installation.
+type Manifest struct {
+	// The name of the installed version, like it is written in the VERSION file.
+	Version string `json:"version"`
+	// Whether the installed release was considered stable at the time of its installation.
+	Stable bool `json:"stable"`
+	// The archive that the installation was extracted from.
+	Archive releases.ReleaseFile `json:"archive"`
+	// The git revision that the installation was built from. Nil, if it was not built from a git revision.
+	Revision *ManifestRevision `json:"revision,omitempty"`
+	// The time of the installation.
+	InstalledAt time.Time `json:"installedAt"`
+	// All files of the installation, sorted by their path.
+	Files []ManifestFile `json:"files"`
+}
+
+// ManifestFile is a struct that describes a single file of an installation.
+type ManifestFile struct {
+	// The path of the file, relative to the installation directory and separated by slashes.
+	Path string `json:"path"`
+	// The size in bytes of the file.
+	Size int64 `json:"size"`
+	// The sha256 checksum of the file. Empty for symbolic links.
+	Sha256 string `json:"sha256,omitempty"`
+	// The target of the file, if it is a symbolic link.
+	Link string `json:"link,omitempty"`
+}
+
+// ManifestRevision is a struct that describes the git revision, that an installation was built from.
+type ManifestRevision struct {
+	// The git remote that the revision was fetched from.
+	Remote string `json:"remote"`
+	// The ref that was requested, like a branch, a tag or a commit hash.
+	Ref string `json:"ref"`
+	// The hash of the commit that the ref pointed to.
+	Commit string `json:"commit"`
+}
+
+// Manifest is a function that reads the manifest, that was recorded when the given version was installed.
+func (m *GoManager) Manifest(versionNumber *version.Version) (*Manifest, error) {
+	return readManifest(m.SDKDirectory(versionNumber), versionNumber)
+}
+
+// Manifest is a function that reads the manifest, that was recorded when the installation was installed.
+func (i Installation) Manifest() (*Manifest, error) {
+	return readManifest(i.Directory, i.Version)
+}
+
+// Size is a function that returns the total size in bytes of all files of the installation.
+func (m *Manifest) Size() int64 {
+	var size int64
+	for _, file := range m.Files {
+		size += file.Size
+	}
+
+	return size
+}
+
+func readManifest(sdkDirectory string, versionNumber *version.Version) (*Manifest, error) {
+	content, err := ioutil.ReadFile(manifestPath(sdkDirectory))
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &Manifest{}
+	if err := json.Unmarshal(content, manifest); err != nil {
+		return nil, fmt.Errorf("manifest of %s is corrupted: %w", toVersionName(versionNumber), err)
+	}
+
+	return manifest, nil
+}
+
+// manifestPath is a function that returns the path of the manifest, that belongs to the installation in a given directory.
+func manifestPath(sdkDirectory string) string {
+	return sdkDirectory + manifestSuffix
+}
+
+func writeManifest(sdkDirectory string, manifest *Manifest) error {
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	manifestFile := manifestPath(sdkDirectory)
+	temporaryFile, err := ioutil.TempFile(filepath.Dir(manifestFile), filepath.Base(manifestFile)+".*")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = os.Remove(temporaryFile.Name())
+	}()
+
+	if _, err := temporaryFile.Write(content); err != nil {
+		_ = temporaryFile.Close()
+		return err
+	}
+	if err := temporaryFile.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(temporaryFile.Name(), manifestFile)
+}
+
+func newManifest(release *releases.Release, file releases.ReleaseFile) *Manifest {
+	return &Manifest{
+		Version: release.Version,
+		Stable:  release.Stable,
+		Archive: file,
+	}
+}
+
+// scanFiles is a function that describes all files inside a given directory, sorted by their path.
+func scanFiles(directory string) ([]ManifestFile, error) {
+	var files []ManifestFile
+
+	err := filepath.Walk(directory, func(path string, fileInfo os.FileInfo, err error) error {
+		if err != nil || fileInfo.IsDir() {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(directory, path)
+		if err != nil {
+			return err
+		}
+
+		file := ManifestFile{Path: filepath.ToSlash(relativePath), Size: fileInfo.Size()}
+		if fileInfo.Mode()&os.ModeSymlink != 0 {
+			file.Link, err = os.Readlink(path)
+		} else {
+			file.Sha256, err = hashFile(path)
+		}
+		if err != nil {
+			return err
+		}
+
+		files = append(files, file)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+
+	return files, nil
+}
+
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path) //nolint:gosec
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
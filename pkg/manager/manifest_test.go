@@ -0,0 +1,76 @@
+package manager
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jangraefen/go-man/pkg/releases"
+	"github.com/jangraefen/go-man/pkg/tasks"
+)
+
+func TestGoManager_Manifest(t *testing.T) {
+	versionNumber := version.Must(version.NewVersion("1.15.2"))
+	tempDir := t.TempDir()
+
+	sut, err := NewManager(&tasks.Task{
+		Output: ioutil.Discard,
+		Error:  ioutil.Discard,
+	}, tempDir)
+	require.NoError(t, err)
+
+	_, err = sut.Manifest(versionNumber)
+	assert.True(t, os.IsNotExist(err))
+
+	file := setupCachedRelease(t, sut, versionNumber)
+	require.NoError(t, sut.Install(versionNumber, runtime.GOOS, runtime.GOARCH, releases.IncludeAll))
+	assert.FileExists(t, filepath.Join(tempDir, "go1.15.2.manifest.json"))
+
+	manifest, err := sut.Manifest(versionNumber)
+	require.NoError(t, err)
+	assert.Equal(t, "go1.15.2", manifest.Version)
+	assert.True(t, manifest.Stable)
+	assert.Equal(t, file, manifest.Archive)
+	assert.False(t, manifest.InstalledAt.IsZero())
+	assert.Equal(t, []ManifestFile{
+		{Path: "VERSION", Size: 8, Sha256: fmt.Sprintf("%x", sha256.Sum256([]byte("go1.15.2")))},
+		{Path: "bin/go", Size: 4, Sha256: fmt.Sprintf("%x", sha256.Sum256([]byte("tool")))},
+	}, manifest.Files)
+	assert.Equal(t, int64(12), manifest.Size())
+
+	installationManifest, err := sut.Installations()[0].Manifest()
+	require.NoError(t, err)
+	assert.Equal(t, manifest, installationManifest)
+
+	require.NoError(t, ioutil.WriteFile(manifestPath(sut.SDKDirectory(versionNumber)), []byte("{"), 0600))
+	_, err = sut.Manifest(versionNumber)
+	assert.Error(t, err)
+
+	require.NoError(t, sut.Uninstall(versionNumber))
+	assert.NoFileExists(t, filepath.Join(tempDir, "go1.15.2.manifest.json"))
+}
+
+func TestScanFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "b", "c"), 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tempDir, "b", "c", "d"), []byte("content"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tempDir, "a"), nil, 0600))
+
+	files, err := scanFiles(tempDir)
+	require.NoError(t, err)
+	assert.Equal(t, []ManifestFile{
+		{Path: "a", Size: 0, Sha256: fmt.Sprintf("%x", sha256.Sum256(nil))},
+		{Path: "b/c/d", Size: 7, Sha256: fmt.Sprintf("%x", sha256.Sum256([]byte("content")))},
+	}, files)
+
+	_, err = scanFiles(filepath.Join(tempDir, "missing"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,130 @@
+package manager
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+)
+
+const (
+	// VersionVariable is the name of the environment variable that overrides the version requested by any version file.
+	VersionVariable = "GMNVERSION"
+
+	versionFileName = ".go-version"
+	moduleFileName  = "go.mod"
+)
+
+// ResolvedVersion is a struct that describes which Go SDK installation applies to a directory and why it was chosen.
+type ResolvedVersion struct {
+	// The installed version that satisfies the request.
+	Version *version.Version
+	// The version as it was requested, e.g. `>= 1.15` for the go directive of a go.mod file.
+	Requested string
+	// The file or environment variable that the requested version was read from. Empty, if the selected version was used as a
+	// fallback.
+	Source string
+}
+
+// Resolve is a function that determines the Go SDK installation that should be used inside a given directory.
+// A version requested by the GMNVERSION environment variable always takes precedence. Otherwise, starting at the given
+// directory, each parent directory is searched for a .go-version file or a go.mod file. The first version requested by one
+// of these sources is matched against the installed versions, choosing the highest installed version that satisfies the
+// request. If no version is requested at all, the currently selected version is used instead.
+func (m *GoManager) Resolve(workingDirectory string) (*ResolvedVersion, error) {
+	requested, source := strings.TrimPrefix(os.Getenv(VersionVariable), "go"), VersionVariable
+	if requested == "" {
+		var err error
+		if requested, source, err = findRequestedVersion(workingDirectory); err != nil {
+			return nil, err
+		}
+	}
+
+	if source == "" {
+		if m.SelectedVersion == nil {
+			return nil, errors.New("no version requested by a version file and no version is selected")
+		}
+
+		return &ResolvedVersion{Version: m.SelectedVersion, Requested: m.SelectedVersion.String()}, nil
+	}
+
+	installedVersions := make(version.Collection, len(m.InstalledVersions))
+	copy(installedVersions, m.InstalledVersions)
+	sort.Sort(sort.Reverse(installedVersions))
+
+	for _, installedVersion := range installedVersions {
+		if matchesRequest(requested, installedVersion) {
+			return &ResolvedVersion{Version: installedVersion, Requested: requested, Source: source}, nil
+		}
+	}
+
+	return nil, NewError(ErrNotInstalled, "version %s requested by %s is not installed", requested, source)
+}
+
+func findRequestedVersion(workingDirectory string) (string, string, error) {
+	directory, err := filepath.Abs(workingDirectory)
+	if err != nil {
+		return "", "", err
+	}
+
+	for {
+		for _, fileName := range []string{versionFileName, moduleFileName} {
+			candidate := filepath.Join(directory, fileName)
+
+			requested, err := readRequestedVersion(candidate)
+			if err != nil {
+				return "", "", err
+			}
+			if requested != "" {
+				return requested, candidate, nil
+			}
+		}
+
+		parent := filepath.Dir(directory)
+		if parent == directory {
+			return "", "", nil
+		}
+
+		directory = parent
+	}
+}
+
+func readRequestedVersion(fileName string) (string, error) {
+	file, err := os.Open(fileName)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var requested string
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.SplitN(scanner.Text(), "//", 2)[0])
+		fields := strings.Fields(line)
+
+		switch {
+		case filepath.Base(fileName) == versionFileName && len(fields) > 0:
+			return strings.TrimPrefix(fields[0], "go"), scanner.Err()
+		case len(fields) == 2 && fields[0] == "go" && requested == "":
+			// The go directive names the minimum version of Go, that the module requires. Since Go 1.21 it is the minimum
+			// toolchain version, which even rejects lower patch releases, so only versions at or above it are acceptable.
+			requested = ">= " + fields[1]
+		case len(fields) == 2 && fields[0] == "toolchain" && fields[1] != "default":
+			// The toolchain directive is more specific than the go directive, so it takes precedence.
+			requested = strings.TrimPrefix(fields[1], "go")
+		}
+	}
+
+	return requested, scanner.Err()
+}
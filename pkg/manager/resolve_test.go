@@ -0,0 +1,147 @@
+package manager
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jangraefen/go-man/pkg/tasks"
+)
+
+func TestGoManager_Resolve(t *testing.T) {
+	selectedVersion := version.Must(version.NewVersion("1.14.9"))
+	olderPatchVersion := version.Must(version.NewVersion("1.15.1"))
+	newerPatchVersion := version.Must(version.NewVersion("1.15.2"))
+
+	tempDir := t.TempDir()
+	projectDirectory := filepath.Join(tempDir, "project")
+	moduleDirectory := filepath.Join(projectDirectory, "module")
+	packageDirectory := filepath.Join(moduleDirectory, "pkg", "sub")
+	require.NoError(t, os.MkdirAll(packageDirectory, 0700))
+
+	sut := &GoManager{
+		RootDirectory:     tempDir,
+		InstalledVersions: version.Collection{newerPatchVersion, selectedVersion, olderPatchVersion},
+		SelectedVersion:   selectedVersion,
+		task: &tasks.Task{
+			Output: os.Stdout,
+			Error:  os.Stderr,
+		},
+	}
+
+	resolved, err := sut.Resolve(packageDirectory)
+	assert.NoError(t, err)
+	assert.True(t, resolved.Version.Equal(selectedVersion))
+	assert.Empty(t, resolved.Source)
+
+	moduleFile := filepath.Join(moduleDirectory, "go.mod")
+	require.NoError(t, ioutil.WriteFile(moduleFile, []byte("module example.org/module\n\ngo 1.15 // comment\n"), 0600))
+
+	resolved, err = sut.Resolve(packageDirectory)
+	assert.NoError(t, err)
+	assert.True(t, resolved.Version.Equal(newerPatchVersion))
+	assert.Equal(t, ">= 1.15", resolved.Requested)
+	assert.Equal(t, moduleFile, resolved.Source)
+
+	require.NoError(t, ioutil.WriteFile(moduleFile, []byte("module example.org/module\n\ngo 1.14.10\n"), 0600))
+
+	resolved, err = sut.Resolve(packageDirectory)
+	assert.NoError(t, err)
+	assert.True(t, resolved.Version.Equal(newerPatchVersion))
+
+	require.NoError(t, ioutil.WriteFile(moduleFile, []byte("module example.org/module\n\ngo 1.15.3\n"), 0600))
+
+	resolved, err = sut.Resolve(packageDirectory)
+	assert.Error(t, err)
+	assert.Nil(t, resolved)
+
+	moduleContent := []byte("module example.org/module\n\ngo 1.15.0\ntoolchain go1.15.1\n")
+	require.NoError(t, ioutil.WriteFile(moduleFile, moduleContent, 0600))
+
+	resolved, err = sut.Resolve(packageDirectory)
+	assert.NoError(t, err)
+	assert.True(t, resolved.Version.Equal(olderPatchVersion))
+	assert.Equal(t, moduleFile, resolved.Source)
+
+	versionFile := filepath.Join(moduleDirectory, ".go-version")
+	require.NoError(t, ioutil.WriteFile(versionFile, []byte("go1.14.9\n"), 0600))
+
+	resolved, err = sut.Resolve(packageDirectory)
+	assert.NoError(t, err)
+	assert.True(t, resolved.Version.Equal(selectedVersion))
+	assert.Equal(t, versionFile, resolved.Source)
+
+	require.NoError(t, ioutil.WriteFile(versionFile, []byte("1.13\n"), 0600))
+
+	resolved, err = sut.Resolve(packageDirectory)
+	assert.Error(t, err)
+	assert.Nil(t, resolved)
+
+	sut.SelectedVersion = nil
+
+	resolved, err = sut.Resolve(projectDirectory)
+	assert.Error(t, err)
+	assert.Nil(t, resolved)
+}
+
+func TestReadRequestedVersion(t *testing.T) {
+	tempDir := t.TempDir()
+	moduleFile := filepath.Join(tempDir, "go.mod")
+
+	requested, err := readRequestedVersion(moduleFile)
+	assert.NoError(t, err)
+	assert.Empty(t, requested)
+
+	require.NoError(t, ioutil.WriteFile(moduleFile, []byte("module example.org/module\n"), 0600))
+
+	requested, err = readRequestedVersion(moduleFile)
+	assert.NoError(t, err)
+	assert.Empty(t, requested)
+
+	require.NoError(t, ioutil.WriteFile(moduleFile, []byte("module example.org/module\ngo 1.21.3\ntoolchain default\n"), 0600))
+
+	requested, err = readRequestedVersion(moduleFile)
+	assert.NoError(t, err)
+	assert.Equal(t, ">= 1.21.3", requested)
+
+	requested, err = readRequestedVersion(tempDir)
+	assert.Error(t, err)
+	assert.Empty(t, requested)
+}
+
+func TestGoManager_Resolve_WithEnvironmentVariable(t *testing.T) {
+	requestedVersion := version.Must(version.NewVersion("1.15.2"))
+	selectedVersion := version.Must(version.NewVersion("1.14.9"))
+
+	t.Cleanup(func() {
+		_ = os.Unsetenv(VersionVariable)
+	})
+
+	sut := &GoManager{
+		RootDirectory:     t.TempDir(),
+		InstalledVersions: version.Collection{requestedVersion, selectedVersion},
+		SelectedVersion:   selectedVersion,
+		task: &tasks.Task{
+			Output: os.Stdout,
+			Error:  os.Stderr,
+		},
+	}
+
+	require.NoError(t, os.Setenv(VersionVariable, "go1.15"))
+
+	resolved, err := sut.Resolve(sut.RootDirectory)
+	assert.NoError(t, err)
+	assert.True(t, resolved.Version.Equal(requestedVersion))
+	assert.Equal(t, VersionVariable, resolved.Source)
+
+	require.NoError(t, os.Setenv(VersionVariable, "1.13"))
+
+	resolved, err = sut.Resolve(sut.RootDirectory)
+	assert.Error(t, err)
+	assert.Nil(t, resolved)
+}
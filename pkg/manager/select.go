@@ -2,8 +2,7 @@ package manager
 
 import (
 	"errors"
-	"fmt"
-	"path/filepath"
+	"runtime"
 
 	"github.com/hashicorp/go-version"
 
@@ -11,26 +10,43 @@ import (
 	"github.com/jangraefen/go-man/pkg/tasks"
 )
 
+const (
+	temporaryLinkSuffix = ".new"
+)
+
 // Select is a function that selects an existing installation of the Go SDK as the active one.
-// Feedback is directly printed to the stdout or stderr, so nothing is returned here.
+// Feedback is reported to the task of the manager, while failures are returned as errors.
 func (m *GoManager) Select(versionNumber *version.Version) error {
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	versionName := toVersionName(versionNumber)
 	m.task.Printf("Selecting version as active: %s", versionName)
 
-	versionDirectory := filepath.Join(m.RootDirectory, fmt.Sprintf("go%s", versionName))
-	if !fileutil.PathExists(versionDirectory) {
-		return fmt.Errorf("version %v was not found", versionName)
+	// Installations for other platforms cannot be run on the current platform, so they must never become the selected one.
+	versionDirectory := m.SDKDirectory(versionNumber)
+	for _, installation := range m.ForeignInstallations {
+		foreign := installation.Directory == versionDirectory || !m.isInstalled(versionNumber)
+		if installation.Version.Equal(versionNumber) && foreign {
+			return NewError(
+				ErrPlatformUnavailable,
+				"version %s is only installed for %s-%s and cannot be selected on %s-%s",
+				versionName, installation.OS, installation.Arch, runtime.GOOS, runtime.GOARCH,
+			)
+		}
 	}
 
-	selectTask := m.task.Step()
-	if m.SelectedVersion != nil {
-		if err := m.unselect(selectTask); err != nil {
-			return err
-		}
+	if !fileutil.PathExists(versionDirectory) {
+		return NewError(ErrNotInstalled, "version %v was not found", versionName)
 	}
 
+	// The selection directory is replaced instead of being unlinked first, so there is no moment without a selected version.
+	selectTask := m.task.Step()
 	linkDescription := "Linking selection directory"
-	linkFunction := func() error { return link(versionDirectory, filepath.Join(m.RootDirectory, selectedDirectoryName)) }
+	linkFunction := func() error { return relink(versionDirectory, m.SelectedDirectory()) }
 	if err := selectTask.Track(linkDescription, linkFunction); err != nil {
 		return err
 	}
@@ -40,8 +56,14 @@ func (m *GoManager) Select(versionNumber *version.Version) error {
 }
 
 // Unselect is a function that unselects an existing installation of the Go SDK as the active one.
-// Feedback is directly printed to the stdout or stderr, so nothing is returned here.
+// Feedback is reported to the task of the manager, while failures are returned as errors.
 func (m *GoManager) Unselect() error {
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	m.task.Printf("Unselect current selected version")
 	if m.SelectedVersion == nil {
 		return errors.New("could not unselect because no version is selected")
@@ -52,7 +74,7 @@ func (m *GoManager) Unselect() error {
 
 func (m *GoManager) unselect(task *tasks.Task) error {
 	unlinkDescription := "Unlinking selection directory"
-	unlinkFunction := func() error { return unlink(filepath.Join(m.RootDirectory, selectedDirectoryName)) }
+	unlinkFunction := func() error { return unlink(m.SelectedDirectory()) }
 	if err := task.Track(unlinkDescription, unlinkFunction); err != nil {
 		return err
 	}
@@ -17,6 +17,22 @@ func link(sourceDirectory, targetDirectory string) error {
 	return os.Symlink(sourceDirectory, targetDirectory)
 }
 
+func relink(sourceDirectory, targetDirectory string) error {
+	// The link is created under a temporary name first and then renamed over the existing link, which replaces it atomically.
+	temporaryLink := targetDirectory + temporaryLinkSuffix
+	_ = os.Remove(temporaryLink)
+
+	if err := os.Symlink(sourceDirectory, temporaryLink); err != nil {
+		return err
+	}
+	if err := os.Rename(temporaryLink, targetDirectory); err != nil {
+		_ = os.Remove(temporaryLink)
+		return err
+	}
+
+	return nil
+}
+
 func unlink(directory string) error {
 	return os.Remove(directory)
 }
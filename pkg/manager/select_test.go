@@ -1,9 +1,11 @@
 package manager
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 
 	"github.com/hashicorp/go-version"
@@ -29,9 +31,8 @@ func TestGoManager_Select(t *testing.T) {
 		InstalledVersions: version.Collection{validVersion, anotherValidVersion},
 		SelectedVersion:   nil,
 		task: &tasks.Task{
-			ErrorExitCode: 1,
-			Output:        os.Stdout,
-			Error:         os.Stderr,
+			Output: os.Stdout,
+			Error:  os.Stderr,
 		},
 	}
 
@@ -45,7 +46,7 @@ func TestGoManager_Select(t *testing.T) {
 	assert.DirExists(t, filepath.Join(tempDir, fmt.Sprintf("go%s", anotherValidVersion)))
 	assert.True(t, fileutil.PathExists(filepath.Join(tempDir, selectedDirectoryName)))
 
-	assert.Error(t, sut.Select(invalidVersion))
+	assert.True(t, errors.Is(sut.Select(invalidVersion), ErrNotInstalled))
 	assert.DirExists(t, filepath.Join(tempDir, fmt.Sprintf("go%s", validVersion)))
 	assert.DirExists(t, filepath.Join(tempDir, fmt.Sprintf("go%s", anotherValidVersion)))
 	assert.True(t, fileutil.PathExists(filepath.Join(tempDir, selectedDirectoryName)))
@@ -61,9 +62,8 @@ func TestGoManager_Select_WithLinkFailure(t *testing.T) {
 		InstalledVersions: version.Collection{invalidVersion},
 		SelectedVersion:   nil,
 		task: &tasks.Task{
-			ErrorExitCode: 1,
-			Output:        os.Stdout,
-			Error:         os.Stderr,
+			Output: os.Stdout,
+			Error:  os.Stderr,
 		},
 	}
 
@@ -74,7 +74,7 @@ func TestGoManager_Select_WithLinkFailure(t *testing.T) {
 	assert.Error(t, sut.Select(invalidVersion))
 }
 
-func TestGoManager_Select_WithFailingUnselect(t *testing.T) {
+func TestGoManager_Select_WithStaleSelection(t *testing.T) {
 	validVersion := version.Must(version.NewVersion("1.15.2"))
 	invalidVersion := version.Must(version.NewVersion("1.14.9"))
 
@@ -87,13 +87,51 @@ func TestGoManager_Select_WithFailingUnselect(t *testing.T) {
 		InstalledVersions: version.Collection{validVersion, invalidVersion},
 		SelectedVersion:   invalidVersion,
 		task: &tasks.Task{
-			ErrorExitCode: 1,
-			Output:        os.Stdout,
-			Error:         os.Stderr,
+			Output: os.Stdout,
+			Error:  os.Stderr,
+		},
+	}
+
+	assert.NoError(t, sut.Select(validVersion))
+	assert.Equal(t, validVersion, sut.SelectedVersion)
+	assert.True(t, fileutil.PathExists(filepath.Join(tempDir, selectedDirectoryName)))
+}
+
+func TestGoManager_Select_ReplacesSelectionAtomically(t *testing.T) {
+	validVersion := version.Must(version.NewVersion("1.15.2"))
+	anotherValidVersion := version.Must(version.NewVersion("1.14.9"))
+
+	tempDir := t.TempDir()
+	selectedPath := filepath.Join(tempDir, selectedDirectoryName)
+
+	setupInstallation(t, tempDir, true, validVersion.String())
+	setupInstallation(t, tempDir, true, anotherValidVersion.String())
+
+	sut := &GoManager{
+		RootDirectory:     tempDir,
+		InstalledVersions: version.Collection{validVersion, anotherValidVersion},
+		task: &tasks.Task{
+			Output: os.Stdout,
+			Error:  os.Stderr,
 		},
 	}
 
-	assert.Error(t, sut.Select(validVersion))
+	require.NoError(t, sut.Select(validVersion))
+
+	// A leftover temporary link of an interrupted selection must neither block the selection nor be kept around.
+	require.NoError(t, link(sut.SDKDirectory(validVersion), selectedPath+temporaryLinkSuffix))
+
+	assert.NoError(t, sut.Select(anotherValidVersion))
+	assert.False(t, fileutil.PathExists(selectedPath+temporaryLinkSuffix))
+
+	detectedVersion, err := detectGoVersion(selectedPath)
+	assert.NoError(t, err)
+	assert.True(t, detectedVersion.Equal(anotherValidVersion))
+
+	manager, err := NewManager(sut.task, tempDir)
+	require.NoError(t, err)
+	assert.Len(t, manager.InstalledVersions, 2)
+	assert.True(t, manager.SelectedVersion.Equal(anotherValidVersion))
 }
 
 func TestGoManager_Select_WithTwoPartVersion(t *testing.T) {
@@ -108,9 +146,8 @@ func TestGoManager_Select_WithTwoPartVersion(t *testing.T) {
 		InstalledVersions: version.Collection{validVersion},
 		SelectedVersion:   nil,
 		task: &tasks.Task{
-			ErrorExitCode: 1,
-			Output:        os.Stdout,
-			Error:         os.Stderr,
+			Output: os.Stdout,
+			Error:  os.Stderr,
 		},
 	}
 
@@ -133,9 +170,8 @@ func TestGoManager_Unselect(t *testing.T) {
 		InstalledVersions: version.Collection{validVersion},
 		SelectedVersion:   nil,
 		task: &tasks.Task{
-			ErrorExitCode: 1,
-			Output:        os.Stdout,
-			Error:         os.Stderr,
+			Output: os.Stdout,
+			Error:  os.Stderr,
 		},
 	}
 
@@ -160,11 +196,33 @@ func TestGoManager_Unselect_WithoutExistingDirectory(t *testing.T) {
 		InstalledVersions: version.Collection{invalidVersion},
 		SelectedVersion:   invalidVersion,
 		task: &tasks.Task{
-			ErrorExitCode: 1,
-			Output:        os.Stdout,
-			Error:         os.Stderr,
+			Output: os.Stdout,
+			Error:  os.Stderr,
 		},
 	}
 
 	assert.Error(t, sut.Unselect())
 }
+
+func TestGoManager_Select_WithForeignInstallation(t *testing.T) {
+	versionNumber := version.Must(version.NewVersion("1.15.2"))
+	tempDir := t.TempDir()
+
+	setupInstallation(t, tempDir, true, "1.15.2")
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "go1.15.2", "pkg", "tool", "plan9_arm"), 0700))
+
+	sut, err := NewManager(&tasks.Task{
+		Output: os.Stdout,
+		Error:  os.Stderr,
+	}, tempDir)
+	require.NoError(t, err)
+
+	err = sut.Select(versionNumber)
+	assert.EqualError(t, err, fmt.Sprintf(
+		"version 1.15.2 is only installed for plan9-arm and cannot be selected on %s-%s",
+		runtime.GOOS, runtime.GOARCH,
+	))
+	assert.True(t, errors.Is(err, ErrPlatformUnavailable))
+	assert.False(t, fileutil.PathExists(sut.SelectedDirectory()))
+	assert.Nil(t, sut.SelectedVersion)
+}
@@ -24,6 +24,30 @@ func link(sourceDirectory, targetDirectory string) error {
 	return exec.Command("cmd", "/c", "mklink", "/J", targetDirectory, sourceDirectory).Run()
 }
 
+func relink(sourceDirectory, targetDirectory string) error {
+	temporaryLink := targetDirectory + temporaryLinkSuffix
+	fileutil.TryRemove(temporaryLink)
+
+	if err := link(sourceDirectory, temporaryLink); err != nil {
+		return err
+	}
+
+	// Junctions are directories, which cannot be replaced by a rename on Windows. Since the new link is created before the
+	// existing one is removed, the window without a selected version is kept as small as possible.
+	if fileutil.PathExists(targetDirectory) {
+		if err := unlink(targetDirectory); err != nil {
+			_ = unlink(temporaryLink)
+			return err
+		}
+	}
+	if err := os.Rename(temporaryLink, targetDirectory); err != nil {
+		_ = unlink(temporaryLink)
+		return err
+	}
+
+	return nil
+}
+
 func unlink(directory string) error {
 	if !fileutil.PathExists(directory) {
 		return fmt.Errorf("%s: no such file or directory", directory)
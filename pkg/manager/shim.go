@@ -0,0 +1,141 @@
+package manager
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/jangraefen/go-man/internal/fileutil"
+)
+
+const (
+	shimDirectoryName = "bin"
+)
+
+var (
+	// ShimTools is the list of Go SDK tools that shims are created for.
+	ShimTools = []string{"go", "gofmt"}
+)
+
+// ShimDirectory is a function that returns the directory that the shims are placed in.
+// To make use of the shims, this directory has to be part of the PATH.
+func (m *GoManager) ShimDirectory() string {
+	return filepath.Join(m.RootDirectory, shimDirectoryName)
+}
+
+// InstallShims is a function that creates a shim for each tool in ShimTools, that dispatches to the given executable.
+// A shim is a link to the gmn executable, which detects the name it was called with and runs the tool of the same name from
+// the resolved Go SDK installation. Existing shims are replaced.
+func (m *GoManager) InstallShims(executable string) error {
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	m.task.Printf("Installing shims into %s", m.ShimDirectory())
+	shimTask := m.task.Step()
+
+	executable, err = filepath.EvalSymlinks(executable)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(m.ShimDirectory(), 0755); err != nil {
+		return err
+	}
+
+	for _, tool := range ShimTools {
+		shimPath := filepath.Join(m.ShimDirectory(), executableName(tool))
+
+		linkDescription := fmt.Sprintf("Linking %s shim", tool)
+		linkFunction := func() error {
+			fileutil.TryRemove(shimPath)
+			return linkShim(executable, shimPath)
+		}
+		if err := shimTask.Track(linkDescription, linkFunction); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RunShim is a function that runs a Go SDK tool from the installation that applies to the given working directory.
+// The installation is determined by Resolve. The standard streams of the current process are passed to the tool.
+func (m *GoManager) RunShim(tool string, args []string, workingDirectory string) error {
+	resolved, err := m.Resolve(workingDirectory)
+	if err != nil {
+		return err
+	}
+
+	sdkDirectory := m.SDKDirectory(resolved.Version)
+	toolPath := filepath.Join(sdkDirectory, "bin", executableName(tool))
+	if !fileutil.PathExists(toolPath) {
+		return fmt.Errorf("tool %s does not exist in %s", tool, sdkDirectory)
+	}
+
+	return runCommand(sdkCommand(sdkDirectory, toolPath, args))
+}
+
+// ShimTool is a function that checks if a program name, usually the first command line argument, refers to a shim.
+// If it does, the name of the tool that the shim stands for is returned.
+func ShimTool(programName string) (string, bool) {
+	name := strings.TrimSuffix(filepath.Base(programName), ".exe")
+
+	for _, tool := range ShimTools {
+		if name == tool {
+			return tool, true
+		}
+	}
+
+	return "", false
+}
+
+func executableName(tool string) string {
+	if runtime.GOOS == "windows" {
+		return tool + ".exe"
+	}
+
+	return tool
+}
+
+func linkShim(executable, shimPath string) error {
+	if runtime.GOOS != "windows" {
+		return os.Symlink(executable, shimPath)
+	}
+
+	// Symbolic links require elevated privileges on Windows, so hard links are used instead. If the shim directory resides on
+	// another volume, even hard links are not possible and the executable is copied as a last resort.
+	if err := os.Link(executable, shimPath); err == nil {
+		return nil
+	}
+
+	return copyFile(executable, shimPath)
+}
+
+func copyFile(sourceFile, targetFile string) error {
+	source, err := os.Open(sourceFile)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = source.Close()
+	}()
+
+	target, err := os.OpenFile(targetFile, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0755) //nolint:gosec
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(target, source); err != nil {
+		_ = target.Close()
+		return err
+	}
+
+	return target.Close()
+}
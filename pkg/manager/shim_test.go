@@ -0,0 +1,94 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jangraefen/go-man/pkg/tasks"
+)
+
+func TestGoManager_InstallShims(t *testing.T) {
+	tempDir := t.TempDir()
+	executable := filepath.Join(t.TempDir(), "gmn")
+	require.NoError(t, ioutil.WriteFile(executable, []byte("executable"), 0600))
+
+	sut := &GoManager{
+		RootDirectory:     tempDir,
+		InstalledVersions: version.Collection{},
+		SelectedVersion:   nil,
+		task: &tasks.Task{
+			Output: os.Stdout,
+			Error:  os.Stderr,
+		},
+	}
+
+	assert.NoError(t, sut.InstallShims(executable))
+	assert.NoError(t, sut.InstallShims(executable))
+
+	for _, tool := range ShimTools {
+		content, err := ioutil.ReadFile(filepath.Join(sut.ShimDirectory(), executableName(tool)))
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("executable"), content)
+	}
+
+	assert.Error(t, sut.InstallShims(filepath.Join(tempDir, "I_DO_NOT_EXIST")))
+}
+
+func TestGoManager_RunShim(t *testing.T) {
+	if runtime.GOOS == "windows" { //nolint:goconst
+		t.Skip("shell scripts cannot be used as tools on windows")
+	}
+
+	validVersion := version.Must(version.NewVersion("1.15.2"))
+	tempDir := t.TempDir()
+	setupInstallation(t, tempDir, true, validVersion.String())
+
+	sut := &GoManager{
+		RootDirectory:     tempDir,
+		InstalledVersions: version.Collection{validVersion},
+		SelectedVersion:   nil,
+		task: &tasks.Task{
+			Output: os.Stdout,
+			Error:  os.Stderr,
+		},
+	}
+
+	assert.Error(t, sut.RunShim("go", nil, tempDir))
+
+	sut.SelectedVersion = validVersion
+	assert.Error(t, sut.RunShim("go", nil, tempDir))
+
+	toolPath := filepath.Join(sut.SDKDirectory(validVersion), "bin", "go")
+	require.NoError(t, os.MkdirAll(filepath.Dir(toolPath), 0700))
+	toolScript := fmt.Sprintf("#!/bin/sh\n[ \"$GOROOT\" = \"%s\" ] && exit $1\nexit 99\n", sut.SDKDirectory(validVersion))
+	require.NoError(t, ioutil.WriteFile(toolPath, []byte(toolScript), 0700)) //nolint:gosec
+
+	err := sut.RunShim("go", []string{"3"}, tempDir)
+	var exitErr *exec.ExitError
+	require.True(t, errors.As(err, &exitErr))
+	assert.Equal(t, 3, exitErr.ExitCode())
+}
+
+func TestShimTool(t *testing.T) {
+	tool, isShim := ShimTool(filepath.Join("root", "bin", "go"))
+	assert.True(t, isShim)
+	assert.Equal(t, "go", tool)
+
+	tool, isShim = ShimTool("gofmt.exe")
+	assert.True(t, isShim)
+	assert.Equal(t, "gofmt", tool)
+
+	tool, isShim = ShimTool("gmn")
+	assert.False(t, isShim)
+	assert.Empty(t, tool)
+}
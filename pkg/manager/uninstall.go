@@ -1,17 +1,21 @@
 package manager
 
 import (
-	"fmt"
 	"os"
-	"path/filepath"
 
 	"github.com/hashicorp/go-version"
 
 	"github.com/jangraefen/go-man/internal/fileutil"
 )
 
-// UninstallAll is a function that removes all current installations of the Go SDK.
+// UninstallAll is a function that removes all current installations of the Go SDK, including those for other platforms.
 func (m *GoManager) UninstallAll() error {
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	installedVersions := make(version.Collection, len(m.InstalledVersions))
 	copy(installedVersions, m.InstalledVersions)
 
@@ -21,12 +25,27 @@ func (m *GoManager) UninstallAll() error {
 		}
 	}
 
+	foreignInstallations := make([]Installation, len(m.ForeignInstallations))
+	copy(foreignInstallations, m.ForeignInstallations)
+
+	for _, installation := range foreignInstallations {
+		if err := m.UninstallForPlatform(installation.Version, installation.OS, installation.Arch); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 // Uninstall is a function that removes an existing installation of the Go SDK.
-// Feedback is directly printed to the stdout or stderr, so nothing is returned here.
+// Feedback is reported to the task of the manager, while failures are returned as errors.
 func (m *GoManager) Uninstall(versionNumber *version.Version) error {
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	versionName := toVersionName(versionNumber)
 
 	m.task.Printf("Uninstalling %s", versionName)
@@ -39,26 +58,77 @@ func (m *GoManager) Uninstall(versionNumber *version.Version) error {
 	}
 
 	removeDescription := "Deleting installation directory"
-	removeFunction := func() error {
-		versionDirectory := filepath.Join(m.RootDirectory, fmt.Sprintf("go%s", versionName))
+	removeFunction := func() error { return removeInstallation(m.SDKDirectory(versionNumber)) }
+	if err := uninstallTask.Track(removeDescription, removeFunction); err != nil {
+		return err
+	}
 
-		if !fileutil.PathExists(versionDirectory) {
-			return fmt.Errorf("no directory %s to uninstall from", versionDirectory)
+	for index, installedVersion := range m.InstalledVersions {
+		if installedVersion.Equal(versionNumber) {
+			m.InstalledVersions = append(m.InstalledVersions[:index], m.InstalledVersions[index+1:]...)
+			break
 		}
+	}
+
+	return nil
+}
 
-		return os.RemoveAll(versionDirectory)
+// UninstallForPlatform is a function that removes an existing installation of the Go SDK, that was built for the given
+// platform. Installations for the current platform are removed like Uninstall does.
+// Feedback is reported to the task of the manager, while failures are returned as errors.
+func (m *GoManager) UninstallForPlatform(versionNumber *version.Version, operatingSystem, arch string) error {
+	if isNativePlatform(operatingSystem, arch) {
+		return m.Uninstall(versionNumber)
 	}
 
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	m.task.Printf("Uninstalling %s %s-%s", toVersionName(versionNumber), operatingSystem, arch)
+	uninstallTask := m.task.Step()
+
+	index := m.foreignInstallationIndex(versionNumber, operatingSystem, arch)
+	if index < 0 {
+		versionName := toVersionName(versionNumber)
+		return NewError(ErrNotInstalled, "version %s is not installed for %s-%s", versionName, operatingSystem, arch)
+	}
+
+	removeDescription := "Deleting installation directory"
+	removeFunction := func() error { return removeInstallation(m.ForeignInstallations[index].Directory) }
 	if err := uninstallTask.Track(removeDescription, removeFunction); err != nil {
 		return err
 	}
 
-	for index, installedVersion := range m.InstalledVersions {
-		if installedVersion.Equal(versionNumber) {
-			m.InstalledVersions = append(m.InstalledVersions[:index], m.InstalledVersions[index+1:]...)
-			break
+	m.ForeignInstallations = append(m.ForeignInstallations[:index], m.ForeignInstallations[index+1:]...)
+	return nil
+}
+
+func (m *GoManager) foreignInstallationIndex(versionNumber *version.Version, operatingSystem, arch string) int {
+	for index, installation := range m.ForeignInstallations {
+		if installation.Version.Equal(versionNumber) && installation.OS == operatingSystem && installation.Arch == arch {
+			return index
 		}
 	}
 
+	return -1
+}
+
+func removeInstallation(sdkDirectory string) error {
+	if !fileutil.PathExists(sdkDirectory) {
+		return NewError(ErrNotInstalled, "no directory %s to uninstall from", sdkDirectory)
+	}
+
+	if err := os.RemoveAll(sdkDirectory); err != nil {
+		return err
+	}
+
+	// Installations from before manifests were recorded do not have one, so a missing manifest is not an error.
+	if err := os.Remove(manifestPath(sdkDirectory)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
 	return nil
 }
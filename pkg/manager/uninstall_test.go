@@ -1,9 +1,12 @@
 package manager
 
 import (
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 
 	"github.com/hashicorp/go-version"
@@ -28,9 +31,8 @@ func TestGoManager_UninstallAll(t *testing.T) {
 		InstalledVersions: version.Collection{validVersion, anotherValidVersion},
 		SelectedVersion:   nil,
 		task: &tasks.Task{
-			ErrorExitCode: 1,
-			Output:        os.Stdout,
-			Error:         os.Stderr,
+			Output: os.Stdout,
+			Error:  os.Stderr,
 		},
 	}
 
@@ -53,9 +55,8 @@ func TestGoManager_UninstallWithTwoPartVersion(t *testing.T) {
 		InstalledVersions: version.Collection{validVersion},
 		SelectedVersion:   nil,
 		task: &tasks.Task{
-			ErrorExitCode: 1,
-			Output:        os.Stdout,
-			Error:         os.Stderr,
+			Output: os.Stdout,
+			Error:  os.Stderr,
 		},
 	}
 
@@ -70,19 +71,23 @@ func TestGoManager_UninstallAll_WithBrokenInstallation(t *testing.T) {
 	tempDir := t.TempDir()
 
 	setupInstallation(t, tempDir, true, validVersion.String())
+	setupInstallation(t, tempDir, false, invalidVersion.String())
 
 	sut := &GoManager{
 		RootDirectory:     tempDir,
 		InstalledVersions: version.Collection{invalidVersion, validVersion},
 		SelectedVersion:   nil,
 		task: &tasks.Task{
-			ErrorExitCode: 1,
-			Output:        os.Stdout,
-			Error:         os.Stderr,
+			Output: os.Stdout,
+			Error:  os.Stderr,
 		},
 	}
 
-	assert.Error(t, sut.UninstallAll())
+	// Broken installations are not detected as installations, so they are kept instead of failing the uninstallation.
+	assert.NoError(t, sut.UninstallAll())
+	assert.NoDirExists(t, filepath.Join(tempDir, "go1.15.2"))
+	assert.DirExists(t, filepath.Join(tempDir, "go1.14.0"))
+	assert.Empty(t, sut.InstalledVersions)
 }
 
 func TestGoManager_Uninstall(t *testing.T) {
@@ -98,9 +103,8 @@ func TestGoManager_Uninstall(t *testing.T) {
 		InstalledVersions: version.Collection{validVersion},
 		SelectedVersion:   validVersion,
 		task: &tasks.Task{
-			ErrorExitCode: 1,
-			Output:        os.Stdout,
-			Error:         os.Stderr,
+			Output: os.Stdout,
+			Error:  os.Stderr,
 		},
 	}
 	require.NoError(t, link(
@@ -108,7 +112,7 @@ func TestGoManager_Uninstall(t *testing.T) {
 		filepath.Join(tempDir, selectedDirectoryName),
 	))
 
-	assert.Error(t, sut.Uninstall(invalidVersion))
+	assert.True(t, errors.Is(sut.Uninstall(invalidVersion), ErrNotInstalled))
 
 	assert.NoError(t, sut.Uninstall(validVersion))
 	assert.NoDirExists(t, filepath.Join(tempDir, fmt.Sprintf("go%s", validVersion)))
@@ -122,3 +126,35 @@ func TestGoManager_Uninstall(t *testing.T) {
 
 	assert.Error(t, sut.Uninstall(validVersion))
 }
+
+func TestGoManager_UninstallForPlatform(t *testing.T) {
+	versionNumber := version.Must(version.NewVersion("1.15.2"))
+	tempDir := t.TempDir()
+
+	setupInstallation(t, tempDir, true, "1.15.2")
+	foreignDirectory := filepath.Join(tempDir, "go1.15.2.plan9-arm")
+	require.NoError(t, os.Rename(filepath.Join(tempDir, "go1.15.2"), foreignDirectory))
+	require.NoError(t, ioutil.WriteFile(manifestPath(foreignDirectory), []byte("{}"), 0600))
+	setupInstallation(t, tempDir, true, "1.15.2")
+
+	sut, err := NewManager(&tasks.Task{
+		Output: os.Stdout,
+		Error:  os.Stderr,
+	}, tempDir)
+	require.NoError(t, err)
+	require.Len(t, sut.ForeignInstallations, 1)
+
+	assert.True(t, errors.Is(sut.UninstallForPlatform(versionNumber, "plan9", "386"), ErrNotInstalled))
+
+	assert.NoError(t, sut.UninstallForPlatform(versionNumber, "plan9", "arm"))
+	assert.NoDirExists(t, foreignDirectory)
+	assert.NoFileExists(t, manifestPath(foreignDirectory))
+	assert.DirExists(t, sut.SDKDirectory(versionNumber))
+	assert.Empty(t, sut.ForeignInstallations)
+
+	assert.Error(t, sut.UninstallForPlatform(versionNumber, "plan9", "arm"))
+
+	assert.NoError(t, sut.UninstallForPlatform(versionNumber, runtime.GOOS, runtime.GOARCH))
+	assert.NoDirExists(t, sut.SDKDirectory(versionNumber))
+	assert.Empty(t, sut.InstalledVersions)
+}
@@ -0,0 +1,96 @@
+package manager
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/go-version"
+
+	"github.com/jangraefen/go-man/internal/fileutil"
+)
+
+// Verify is a function that checks if an existing installation of the Go SDK still matches the manifest, that was recorded
+// during its installation. Missing, truncated, modified and additional files are reported individually. If the version
+// is not installed, an ErrNotInstalled error is returned, while any deviation results in an ErrChecksumMismatch error.
+// Installations without a manifest cannot be verified and result in a generic error.
+// Feedback is reported to the task of the manager, while failures are returned as errors.
+func (m *GoManager) Verify(versionNumber *version.Version) error {
+	versionName := toVersionName(versionNumber)
+	if !fileutil.PathExists(m.SDKDirectory(versionNumber)) {
+		return NewError(ErrNotInstalled, "version %s is not installed", versionName)
+	}
+
+	m.task.Printf("Verifying %s", versionName)
+	verifyTask := m.task.Step()
+
+	var deviations []string
+	compareDescription := "Comparing installation with manifest"
+	compareFunction := func() error {
+		manifest, err := m.Manifest(versionNumber)
+		// Installations from before manifests were recorded cannot be verified, until they are installed again.
+		if os.IsNotExist(err) {
+			return fmt.Errorf("no manifest available for %s, reinstall it to record one", versionName)
+		}
+		if err != nil {
+			return err
+		}
+
+		deviations, err = compareManifest(manifest, m.SDKDirectory(versionNumber))
+		if err != nil {
+			return err
+		}
+		if len(deviations) > 0 {
+			message := "installation of %s deviates from its manifest in %d file(s)"
+			return NewError(ErrChecksumMismatch, message, versionName, len(deviations))
+		}
+
+		return nil
+	}
+
+	if err := verifyTask.Track(compareDescription, compareFunction); err != nil {
+		for _, deviation := range deviations {
+			verifyTask.Step().Printf("%s", deviation)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func compareManifest(manifest *Manifest, sdkDirectory string) ([]string, error) {
+	actualFiles, err := scanFiles(sdkDirectory)
+	if err != nil {
+		return nil, err
+	}
+
+	actualFilesByPath := make(map[string]ManifestFile, len(actualFiles))
+	for _, actualFile := range actualFiles {
+		actualFilesByPath[actualFile.Path] = actualFile
+	}
+
+	var deviations []string
+	for _, expectedFile := range manifest.Files {
+		actualFile, exists := actualFilesByPath[expectedFile.Path]
+		delete(actualFilesByPath, expectedFile.Path)
+
+		switch {
+		case !exists:
+			deviations = append(deviations, "missing: "+expectedFile.Path)
+		case actualFile.Link != expectedFile.Link:
+			deviations = append(deviations, "modified: "+expectedFile.Path)
+		case actualFile.Size < expectedFile.Size:
+			deviations = append(deviations, "truncated: "+expectedFile.Path)
+		case actualFile.Size != expectedFile.Size || actualFile.Sha256 != expectedFile.Sha256:
+			deviations = append(deviations, "modified: "+expectedFile.Path)
+		}
+	}
+
+	for _, actualFile := range actualFiles {
+		if _, added := actualFilesByPath[actualFile.Path]; added {
+			deviations = append(deviations, "added: "+actualFile.Path)
+		}
+	}
+
+	return deviations, nil
+}
@@ -0,0 +1,92 @@
+package manager
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jangraefen/go-man/pkg/releases"
+	"github.com/jangraefen/go-man/pkg/tasks"
+)
+
+func TestGoManager_Verify(t *testing.T) {
+	versionNumber := version.Must(version.NewVersion("1.15.2"))
+	tempDir := t.TempDir()
+	output := &bytes.Buffer{}
+
+	sut, err := NewManager(&tasks.Task{
+		Output: output,
+		Error:  output,
+	}, tempDir)
+	require.NoError(t, err)
+
+	err = sut.Verify(versionNumber)
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrNotInstalled))
+
+	setupCachedRelease(t, sut, versionNumber)
+	require.NoError(t, sut.Install(versionNumber, runtime.GOOS, runtime.GOARCH, releases.IncludeAll))
+	assert.NoError(t, sut.Verify(versionNumber))
+
+	sdkDirectory := sut.SDKDirectory(versionNumber)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(sdkDirectory, "bin", "go"), []byte("tampered"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(sdkDirectory, "bin", "extra"), []byte("extra"), 0600))
+
+	output.Reset()
+	err = sut.Verify(versionNumber)
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrChecksumMismatch))
+	assert.Contains(t, output.String(), "modified: bin/go")
+	assert.Contains(t, output.String(), "added: bin/extra")
+
+	// An installation, that was installed before manifests were recorded, is still installed.
+	require.NoError(t, os.Remove(manifestPath(sdkDirectory)))
+
+	err = sut.Verify(versionNumber)
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrNotInstalled))
+	assert.Contains(t, err.Error(), "reinstall")
+
+	require.NoError(t, ioutil.WriteFile(manifestPath(sdkDirectory), []byte("corrupted"), 0600))
+
+	err = sut.Verify(versionNumber)
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrNotInstalled))
+	assert.False(t, errors.Is(err, ErrChecksumMismatch))
+}
+
+func TestCompareManifest(t *testing.T) {
+	tempDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "bin"), 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tempDir, "VERSION"), []byte("go1.15.2"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tempDir, "bin", "go"), []byte("tool"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tempDir, "bin", "gofmt"), []byte("tool"), 0600))
+
+	files, err := scanFiles(tempDir)
+	require.NoError(t, err)
+	manifest := &Manifest{Version: "go1.15.2", Files: files}
+
+	deviations, err := compareManifest(manifest, tempDir)
+	require.NoError(t, err)
+	assert.Empty(t, deviations)
+
+	require.NoError(t, os.Remove(filepath.Join(tempDir, "VERSION")))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tempDir, "bin", "go"), []byte("to"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tempDir, "bin", "gofmt"), []byte("fake"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tempDir, "bin", "vet"), []byte("tool"), 0600))
+
+	deviations, err = compareManifest(manifest, tempDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"missing: VERSION", "truncated: bin/go", "modified: bin/gofmt", "added: bin/vet"}, deviations)
+
+	_, err = compareManifest(manifest, filepath.Join(tempDir, "missing"))
+	assert.Error(t, err)
+}
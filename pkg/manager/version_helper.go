@@ -7,6 +7,12 @@ import (
 	"github.com/hashicorp/go-version"
 )
 
+// VersionName is a function that returns the name of a version, like it is used for the directory of its installation.
+// Unlike the original version string, trailing zero segments are omitted, so "1.16.0" is named "1.16".
+func VersionName(versionNumber *version.Version) string {
+	return toVersionName(versionNumber)
+}
+
 func toVersionName(versionNumber *version.Version) string {
 	segments := versionNumber.Segments()
 	segmentsLen := len(segments)
@@ -30,5 +36,36 @@ func toVersionName(versionNumber *version.Version) string {
 		segmentNames = append([]string{strconv.Itoa(segment)}, segmentNames...)
 	}
 
-	return strings.Join(segmentNames, ".")
+	return strings.Join(segmentNames, ".") + versionNumber.Prerelease()
+}
+
+// isDevelVersion is a function that checks if a version names a development build, that was built from a git revision.
+func isDevelVersion(versionNumber *version.Version) bool {
+	return strings.HasPrefix(versionNumber.Prerelease(), "devel")
+}
+
+// matchesRequest is a function that checks if an installed version satisfies a requested version string.
+// A request that names less than three segments, like `1.15`, is treated as a release line and is satisfied by every
+// patch release of that line. A request that is a constraint, like `>= 1.21.3`, is satisfied by every version that meets
+// it. Any other request has to match the installed version exactly.
+func matchesRequest(requested string, versionNumber *version.Version) bool {
+	requestedVersion, err := version.NewVersion(requested)
+	if err != nil {
+		constraints, err := version.NewConstraint(requested)
+		return err == nil && constraints.Check(versionNumber)
+	}
+
+	if strings.Count(strings.Split(requested, "-")[0], ".") >= 2 || requestedVersion.Prerelease() != "" {
+		return requestedVersion.Equal(versionNumber)
+	}
+
+	requestedSegments := requestedVersion.Segments()
+	installedSegments := versionNumber.Segments()
+	for index := 0; index <= strings.Count(requested, "."); index++ {
+		if requestedSegments[index] != installedSegments[index] {
+			return false
+		}
+	}
+
+	return versionNumber.Prerelease() == ""
 }
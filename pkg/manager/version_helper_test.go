@@ -12,3 +12,30 @@ func Test_toVersionName(t *testing.T) {
 	assert.Equal(t, "1.16", toVersionName(version.Must(version.NewVersion("1.16.0.0.0"))))
 	assert.Equal(t, "1.16.0.1", toVersionName(version.Must(version.NewVersion("1.16.0.1.0"))))
 }
+
+func Test_toVersionName_WithPrerelease(t *testing.T) {
+	assert.Equal(t, "1.16rc1", toVersionName(version.Must(version.NewVersion("1.16rc1"))))
+	assert.Equal(t, "1.16beta1", toVersionName(version.Must(version.NewVersion("1.16-beta1"))))
+}
+
+func TestVersionName(t *testing.T) {
+	assert.Equal(t, "1.16", VersionName(version.Must(version.NewVersion("1.16.0"))))
+	assert.Equal(t, "1.22devel.0123456789ab", VersionName(version.Must(version.NewVersion("1.22-devel.0123456789ab"))))
+}
+
+func Test_matchesRequest(t *testing.T) {
+	assert.True(t, matchesRequest("1.15", version.Must(version.NewVersion("1.15.2"))))
+	assert.True(t, matchesRequest("1.15", version.Must(version.NewVersion("1.15"))))
+	assert.True(t, matchesRequest("1", version.Must(version.NewVersion("1.15.2"))))
+	assert.True(t, matchesRequest("1.15.2", version.Must(version.NewVersion("1.15.2"))))
+	assert.True(t, matchesRequest("1.16rc1", version.Must(version.NewVersion("1.16rc1"))))
+	assert.True(t, matchesRequest(">= 1.21.3", version.Must(version.NewVersion("1.21.3"))))
+	assert.True(t, matchesRequest(">= 1.21", version.Must(version.NewVersion("1.22.1"))))
+
+	assert.False(t, matchesRequest("1.15", version.Must(version.NewVersion("1.14.9"))))
+	assert.False(t, matchesRequest("1.16", version.Must(version.NewVersion("1.16rc1"))))
+	assert.False(t, matchesRequest("1.15.0", version.Must(version.NewVersion("1.15.2"))))
+	assert.False(t, matchesRequest(">= 1.21.3", version.Must(version.NewVersion("1.21.0"))))
+	assert.False(t, matchesRequest(">= 1.21", version.Must(version.NewVersion("1.22rc1"))))
+	assert.False(t, matchesRequest("invalid", version.Must(version.NewVersion("1.15.2"))))
+}
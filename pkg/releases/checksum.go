@@ -0,0 +1,85 @@
+package releases
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jangraefen/go-man/internal/httputil"
+)
+
+const (
+	// OfficialChecksumBaseURL is the base URL of the official download server, where a checksum file is served next to
+	// each release file. It is a different origin than the official Golang website, that the release list is served at.
+	OfficialChecksumBaseURL = "https://dl.google.com/go/"
+
+	checksumSuffix    = ".sha256"
+	checksumSizeLimit = 1024
+)
+
+var (
+	// ChecksumBaseURL holds the base URL that checksum files of release files are fetched from, to verify them independently
+	// of the release source. By default, the official download server is used. If empty, no checksum files are fetched.
+	ChecksumBaseURL = OfficialChecksumBaseURL
+)
+
+// GetChecksumURL is a getter that returns the URL where the checksum file of the receiving file can be downloaded from.
+// The URL is determined by the configured checksum base URL and is empty, if checksum files are not fetched.
+func (f ReleaseFile) GetChecksumURL() string {
+	if len(f.Filename) == 0 || ChecksumBaseURL == "" {
+		return ""
+	}
+
+	return withTrailingSlash(ChecksumBaseURL) + f.Filename + checksumSuffix
+}
+
+// FetchChecksum is a function that retrieves the sha256 checksum of the receiving file from its checksum file.
+// Since the checksum file is served by another origin than the release list, it allows to verify a downloaded file without
+// trusting the release source alone.
+func (f ReleaseFile) FetchChecksum() (string, error) {
+	checksumURL := f.GetChecksumURL()
+	if checksumURL == "" {
+		return "", fmt.Errorf("no checksum file available for %s", f.Filename)
+	}
+
+	content, err := httputil.GetText(checksumURL, checksumSizeLimit)
+	if err != nil {
+		return "", err
+	}
+
+	// Checksum files either contain only the checksum or have the format of the sha256sum tool, that appends the file name.
+	fields := strings.Fields(content)
+	if len(fields) == 0 || !IsChecksum(fields[0]) {
+		return "", fmt.Errorf("checksum file %s does not contain a sha256 checksum", checksumURL)
+	}
+
+	return strings.ToLower(fields[0]), nil
+}
+
+// Checksum is a function that calculates the sha256 checksum of a given file.
+func Checksum(fileName string) (string, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+// IsChecksum is a function that checks if a given string is a hex encoded sha256 checksum.
+func IsChecksum(checksum string) bool {
+	decoded, err := hex.DecodeString(checksum)
+	return err == nil && len(decoded) == sha256.Size
+}
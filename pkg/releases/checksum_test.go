@@ -0,0 +1,92 @@
+package releases
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testChecksum = "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
+)
+
+func TestReleaseFile_GetChecksumURL(t *testing.T) {
+	t.Cleanup(func() {
+		ChecksumBaseURL = OfficialChecksumBaseURL
+	})
+
+	sut := &ReleaseFile{}
+	assert.Empty(t, sut.GetChecksumURL())
+
+	sut.Filename = "go1.15.2.windows-amd64.zip"
+	assert.Equal(t, "https://dl.google.com/go/go1.15.2.windows-amd64.zip.sha256", sut.GetChecksumURL())
+
+	ChecksumBaseURL = "https://checksums.example.org"
+	assert.Equal(t, "https://checksums.example.org/go1.15.2.windows-amd64.zip.sha256", sut.GetChecksumURL())
+
+	ChecksumBaseURL = ""
+	assert.Empty(t, sut.GetChecksumURL())
+}
+
+func TestReleaseFile_FetchChecksum(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/plain.zip.sha256":
+			_, _ = w.Write([]byte(testChecksum + "\n"))
+		case "/sha256sum.zip.sha256":
+			_, _ = w.Write([]byte("B94D27B9934D3E08A52E52D7DA7DABFAC484EFE37A5380EE9088F7ACE2EFCDE9  sha256sum.zip\n"))
+		case "/invalid.zip.sha256":
+			_, _ = w.Write([]byte("<html>not a checksum</html>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	ChecksumBaseURL = server.URL
+	t.Cleanup(func() {
+		ChecksumBaseURL = OfficialChecksumBaseURL
+	})
+
+	checksum, err := ReleaseFile{Filename: "plain.zip"}.FetchChecksum()
+	assert.NoError(t, err)
+	assert.Equal(t, testChecksum, checksum)
+
+	checksum, err = ReleaseFile{Filename: "sha256sum.zip"}.FetchChecksum()
+	assert.NoError(t, err)
+	assert.Equal(t, testChecksum, checksum)
+
+	_, err = ReleaseFile{Filename: "invalid.zip"}.FetchChecksum()
+	assert.Error(t, err)
+
+	_, err = ReleaseFile{Filename: "missing.zip"}.FetchChecksum()
+	assert.Error(t, err)
+
+	ChecksumBaseURL = ""
+	_, err = ReleaseFile{Filename: "plain.zip"}.FetchChecksum()
+	assert.Error(t, err)
+}
+
+func TestChecksum(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "hello.txt")
+	require.NoError(t, ioutil.WriteFile(fileName, []byte("hello world"), 0600))
+
+	checksum, err := Checksum(fileName)
+	assert.NoError(t, err)
+	assert.Equal(t, testChecksum, checksum)
+
+	_, err = Checksum(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Error(t, err)
+}
+
+func TestIsChecksum(t *testing.T) {
+	assert.True(t, IsChecksum(testChecksum))
+	assert.False(t, IsChecksum(""))
+	assert.False(t, IsChecksum(testChecksum[1:]))
+	assert.False(t, IsChecksum("z"+testChecksum[1:]))
+}
@@ -0,0 +1,76 @@
+package releases
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+)
+
+// ParseConstraints is a function that parses an expression of comma-separated version constraints.
+// Next to the operators that are understood by version.NewConstraint, the following shorthands are supported:
+// Wildcards like `1.15.x` or `1.15.*` match every patch release of a release line, tilde ranges like `~1.15` match the
+// highest patch release of a release line and caret ranges like `^1.15` match the highest release with the same major
+// version.
+func ParseConstraints(expression string) (version.Constraints, error) {
+	var constraints version.Constraints
+
+	for _, part := range strings.Split(expression, ",") {
+		part = strings.TrimSpace(part)
+
+		translated, err := translateConstraint(part)
+		if err != nil {
+			return nil, err
+		}
+
+		constraint, err := version.NewConstraint(translated)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version constraint %q: %w", part, err)
+		}
+
+		constraints = append(constraints, constraint...)
+	}
+
+	return constraints, nil
+}
+
+func translateConstraint(constraint string) (string, error) {
+	switch {
+	case strings.HasSuffix(constraint, ".x") || strings.HasSuffix(constraint, ".*"):
+		prefix := strings.TrimPrefix(constraint[:len(constraint)-2], "go")
+		lower, err := version.NewVersion(prefix)
+		if err != nil {
+			return "", fmt.Errorf("invalid version constraint %q: %w", constraint, err)
+		}
+
+		upper := nextVersion(lower, strings.Count(prefix, "."))
+		return fmt.Sprintf(">= %s, < %s", lower, upper), nil
+	case strings.HasPrefix(constraint, "~") && !strings.HasPrefix(constraint, "~>"):
+		lower := strings.TrimSpace(strings.TrimPrefix(constraint, "~"))
+		if strings.Count(lower, ".") < 2 {
+			lower += ".0"
+		}
+		return "~> " + lower, nil
+	case strings.HasPrefix(constraint, "^"):
+		lower, err := version.NewVersion(strings.TrimSpace(strings.TrimPrefix(constraint, "^")))
+		if err != nil {
+			return "", fmt.Errorf("invalid version constraint %q: %w", constraint, err)
+		}
+
+		return fmt.Sprintf(">= %s, < %s", lower, nextVersion(lower, 0)), nil
+	default:
+		return constraint, nil
+	}
+}
+
+func nextVersion(versionNumber *version.Version, segment int) string {
+	segments := versionNumber.Segments()
+	segments[segment]++
+
+	names := make([]string, segment+1)
+	for index := range names {
+		names[index] = fmt.Sprint(segments[index])
+	}
+
+	return strings.Join(names, ".")
+}
@@ -0,0 +1,46 @@
+package releases
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConstraints(t *testing.T) {
+	tests := []struct {
+		expression string
+		matching   []string
+		other      []string
+	}{
+		{"1.15.x", []string{"1.15", "1.15.2"}, []string{"1.14.9", "1.16", "1.16rc1"}},
+		{"go1.15.*", []string{"1.15.0", "1.15.9"}, []string{"1.16.0"}},
+		{"1.x", []string{"1.0", "1.15.2"}, []string{"2.0"}},
+		{"~1.15", []string{"1.15", "1.15.8"}, []string{"1.14.9", "1.16"}},
+		{"~1.15.2", []string{"1.15.2", "1.15.8"}, []string{"1.15.1", "1.16"}},
+		{"^1.14", []string{"1.14", "1.16.3"}, []string{"1.13.9", "2.0"}},
+		{">=1.14,<1.16", []string{"1.14", "1.15.2"}, []string{"1.13.15", "1.16"}},
+		{">= 1.14, < 1.16, != 1.15.1", []string{"1.15.2"}, []string{"1.15.1"}},
+		{"~> 1.15.0", []string{"1.15.5"}, []string{"1.16"}},
+	}
+
+	for _, test := range tests {
+		constraints, err := ParseConstraints(test.expression)
+		if !assert.NoError(t, err, test.expression) {
+			continue
+		}
+
+		for _, matching := range test.matching {
+			assert.True(t, constraints.Check(version.Must(version.NewVersion(matching))), "%s: %s", test.expression, matching)
+		}
+		for _, other := range test.other {
+			assert.False(t, constraints.Check(version.Must(version.NewVersion(other))), "%s: %s", test.expression, other)
+		}
+	}
+
+	for _, invalid := range []string{"latest", "x", "abc.x", "^abc", ">= 1.14,"} {
+		constraints, err := ParseConstraints(invalid)
+		assert.Error(t, err, invalid)
+		assert.Nil(t, constraints, invalid)
+	}
+}
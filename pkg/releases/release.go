@@ -1,10 +1,6 @@
 package releases
 
 import (
-	"crypto/sha256"
-	"fmt"
-	"io"
-	"os"
 	"strings"
 
 	"github.com/hashicorp/go-version"
@@ -14,8 +10,6 @@ import (
 type FileKind string
 
 const (
-	fileURLTemplate = "https://golang.org/dl/%s"
-
 	// SourceFile describes the file kind source archiveutil of the Golang SDK release.
 	SourceFile = FileKind("source")
 	// ArchiveFile describes the file kind binary distribution archiveutil of the Golang SDK release.
@@ -95,32 +89,23 @@ type ReleaseFile struct {
 }
 
 // GetURL is a getter that returns the URL where the receiving file can be downloaded from.
+// The URL is determined by the configured release source.
 func (f ReleaseFile) GetURL() string {
 	if len(f.Filename) == 0 {
 		return ""
 	}
 
-	return fmt.Sprintf(fileURLTemplate, f.Filename)
+	return Source.FileURL(f.Filename)
 }
 
 // VerifySame is a function that checks if a given file has the correct checksum.
 // It first builds the sha256 of the given file and then compares that value against the Sha256 attribute.
 func (f ReleaseFile) VerifySame(fileName string) (bool, error) {
-	file, err := os.Open(fileName)
+	checksum, err := Checksum(fileName)
 	if err != nil {
 		return false, err
 	}
 
-	defer func() {
-		_ = file.Close()
-	}()
-
-	hash := sha256.New()
-	if _, err := io.Copy(hash, file); err != nil {
-		return false, err
-	}
-
-	checksum := fmt.Sprintf("%x", hash.Sum(nil))
 	return f.Sha256 == checksum, nil
 }
 
@@ -0,0 +1,189 @@
+package releases
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// DefaultCacheTTL is the duration that a cached release list is used for, before it is revalidated.
+	DefaultCacheTTL = time.Hour
+	// CacheFilePattern is the glob pattern that matches the files, that a CachedSource caches release lists in.
+	CacheFilePattern = "releases-*.json"
+
+	cacheFileTemplate = "releases-%s-%s.json"
+)
+
+// CachedSource is a release source that persists the release lists of another source in a cache directory.
+// A cached release list is used as long as it is younger than the TTL. Afterwards, it is revalidated with the wrapped
+// source, which avoids downloading the release list again if the wrapped source is a RevalidatingSource and the release
+// list was not modified. If the wrapped source fails, a stale release list is used as a fallback, unless a refresh was
+// requested. Release lists of different sources are cached in different files, so switching the source never serves the
+// release list of the previous one.
+type CachedSource struct {
+	// The release source whose release lists are cached.
+	Source ReleaseSource
+	// The directory that the release lists are cached in.
+	Directory string
+	// The duration that a cached release list is used for, before it is revalidated.
+	TTL time.Duration
+	// If set, the wrapped source is never used and only cached release lists are available.
+	Offline bool
+	// If set, cached release lists are revalidated, regardless of their age.
+	Refresh bool
+}
+
+type cachedReleaseList struct {
+	FetchedAt time.Time `json:"fetchedAt"`
+	Validators
+	Releases Collection `json:"releases"`
+}
+
+// ListReleases is a function that returns the cached release list, refreshing it with the wrapped source when necessary.
+func (s *CachedSource) ListReleases(releaseType ReleaseType) (Collection, error) {
+	cacheFile := s.cacheFile(releaseType)
+
+	cached, err := readCachedReleaseList(cacheFile)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	// While offline, the stable releases can still be derived from a cached list of all releases.
+	if s.Offline && cached == nil && releaseType == IncludeStable {
+		allReleases, err := readCachedReleaseList(s.cacheFile(IncludeAll))
+		if err == nil && allReleases != nil {
+			return filterStable(allReleases.Releases), nil
+		}
+	}
+
+	switch {
+	case s.Offline && cached == nil:
+		return nil, errors.New("no cached release list is available while offline")
+	case s.Offline, cached != nil && !s.Refresh && time.Since(cached.FetchedAt) < s.TTL:
+		return cached.Releases, nil
+	}
+
+	fetched, err := s.fetch(releaseType, cached)
+	if err != nil {
+		if cached != nil && !s.Refresh {
+			return cached.Releases, nil
+		}
+
+		return nil, err
+	}
+
+	if err := writeCachedReleaseList(cacheFile, fetched); err != nil {
+		return nil, err
+	}
+
+	return fetched.Releases, nil
+}
+
+// FileURL is a function that returns the download URL of the wrapped source.
+func (s *CachedSource) FileURL(fileName string) string {
+	return s.Source.FileURL(fileName)
+}
+
+func (s *CachedSource) fetch(releaseType ReleaseType, cached *cachedReleaseList) (*cachedReleaseList, error) {
+	revalidatingSource, revalidating := s.Source.(RevalidatingSource)
+	if !revalidating {
+		releaseList, err := s.Source.ListReleases(releaseType)
+		if err != nil {
+			return nil, err
+		}
+
+		return &cachedReleaseList{FetchedAt: time.Now(), Releases: releaseList}, nil
+	}
+
+	var validators Validators
+	if cached != nil {
+		validators = cached.Validators
+	}
+
+	releaseList, validators, modified, err := revalidatingSource.ListReleasesIfModified(releaseType, validators)
+	if err != nil {
+		return nil, err
+	}
+	if !modified && cached != nil {
+		return &cachedReleaseList{FetchedAt: time.Now(), Validators: cached.Validators, Releases: cached.Releases}, nil
+	}
+	if !modified {
+		// Without a cached release list, there is nothing that could have been revalidated, so it is retrieved in full.
+		if releaseList, err = s.Source.ListReleases(releaseType); err != nil {
+			return nil, err
+		}
+		validators = Validators{}
+	}
+
+	return &cachedReleaseList{FetchedAt: time.Now(), Validators: validators, Releases: releaseList}, nil
+}
+
+// cacheFile is a function that returns the path of the file, that the release list of the given type is cached in. The
+// file name contains a hash of the wrapped source, so that each source has its own cache file.
+func (s *CachedSource) cacheFile(releaseType ReleaseType) string {
+	var identity string
+	switch source := s.Source.(type) {
+	case *MirrorSource:
+		identity = "mirror:" + source.BaseURL
+	case *FileSource:
+		identity = "file:" + source.Path
+	default:
+		identity = fmt.Sprintf("%T", source)
+	}
+
+	hash := sha256.Sum256([]byte(identity))
+	return filepath.Join(s.Directory, fmt.Sprintf(cacheFileTemplate, releaseType, hex.EncodeToString(hash[:])[:12]))
+}
+
+func readCachedReleaseList(cacheFile string) (*cachedReleaseList, error) {
+	content, err := ioutil.ReadFile(cacheFile)
+	if err != nil {
+		return nil, err
+	}
+
+	cached := &cachedReleaseList{}
+	if err := json.Unmarshal(content, cached); err != nil || cached.Releases == nil {
+		// A corrupted cache is treated like a missing one, since it will be overwritten by the next successful fetch.
+		return nil, nil
+	}
+
+	return cached, nil
+}
+
+func writeCachedReleaseList(cacheFile string, cached *cachedReleaseList) error {
+	content, err := json.Marshal(cached)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cacheFile), 0755); err != nil {
+		return err
+	}
+
+	// The release list is written to a temporary file first, so that concurrent readers never observe a partial file.
+	temporaryFile, err := ioutil.TempFile(filepath.Dir(cacheFile), filepath.Base(cacheFile)+".*")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = os.Remove(temporaryFile.Name())
+	}()
+
+	if _, err := temporaryFile.Write(content); err != nil {
+		_ = temporaryFile.Close()
+		return err
+	}
+	if err := temporaryFile.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(temporaryFile.Name(), cacheFile)
+}
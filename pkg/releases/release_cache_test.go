@@ -0,0 +1,220 @@
+package releases
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachedSource_ListReleases(t *testing.T) {
+	var requests, fullResponses int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		fullResponses++
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(releaseListJSON))
+	}))
+	t.Cleanup(server.Close)
+
+	cacheDirectory := t.TempDir()
+	sut := &CachedSource{
+		Source:    &MirrorSource{BaseURL: server.URL},
+		Directory: cacheDirectory,
+		TTL:       time.Hour,
+	}
+
+	releaseList, err := sut.ListReleases(IncludeAll)
+	assert.NoError(t, err)
+	assert.Len(t, releaseList, 2)
+	assert.FileExists(t, sut.cacheFile(IncludeAll))
+	assert.Equal(t, 1, requests)
+
+	releaseList, err = sut.ListReleases(IncludeAll)
+	assert.NoError(t, err)
+	assert.Len(t, releaseList, 2)
+	assert.Equal(t, 1, requests)
+
+	sut.Refresh = true
+
+	releaseList, err = sut.ListReleases(IncludeAll)
+	assert.NoError(t, err)
+	assert.Len(t, releaseList, 2)
+	assert.Equal(t, 2, requests)
+	assert.Equal(t, 1, fullResponses)
+
+	sut.Refresh = false
+	sut.TTL = 0
+
+	releaseList, err = sut.ListReleases(IncludeAll)
+	assert.NoError(t, err)
+	assert.Len(t, releaseList, 2)
+	assert.Equal(t, 3, requests)
+	assert.Equal(t, 1, fullResponses)
+}
+
+func TestCachedSource_ListReleases_Offline(t *testing.T) {
+	cacheDirectory := t.TempDir()
+	sut := &CachedSource{
+		Source:    failingSource{},
+		Directory: cacheDirectory,
+		TTL:       time.Hour,
+		Offline:   true,
+	}
+
+	releaseList, err := sut.ListReleases(IncludeAll)
+	assert.Error(t, err)
+	assert.Nil(t, releaseList)
+
+	require.NoError(t, writeCachedReleaseList(sut.cacheFile(IncludeAll), &cachedReleaseList{
+		FetchedAt: time.Now().Add(-24 * time.Hour),
+		Releases:  Collection{{Version: "go1.15.2"}},
+	}))
+
+	releaseList, err = sut.ListReleases(IncludeAll)
+	assert.NoError(t, err)
+	assert.Len(t, releaseList, 1)
+
+	sut.Offline = false
+
+	releaseList, err = sut.ListReleases(IncludeAll)
+	assert.NoError(t, err)
+	assert.Len(t, releaseList, 1)
+
+	sut.Refresh = true
+
+	releaseList, err = sut.ListReleases(IncludeAll)
+	assert.Error(t, err)
+	assert.Nil(t, releaseList)
+}
+
+func TestCachedSource_ListReleases_WithCorruptedCache(t *testing.T) {
+	releaseFile := filepath.Join(t.TempDir(), "releases.json")
+	require.NoError(t, ioutil.WriteFile(releaseFile, []byte(releaseListJSON), 0600))
+
+	sut := &CachedSource{
+		Source:    &FileSource{Path: releaseFile},
+		Directory: t.TempDir(),
+		TTL:       time.Hour,
+	}
+
+	cacheFile := sut.cacheFile(IncludeStable)
+	require.NoError(t, ioutil.WriteFile(cacheFile, []byte("not json"), 0600))
+
+	releaseList, err := sut.ListReleases(IncludeStable)
+	assert.NoError(t, err)
+	assert.Len(t, releaseList, 1)
+
+	cached, err := readCachedReleaseList(cacheFile)
+	assert.NoError(t, err)
+	assert.Len(t, cached.Releases, 1)
+
+	require.NoError(t, os.Remove(cacheFile))
+	require.NoError(t, os.Mkdir(cacheFile, 0700))
+
+	releaseList, err = sut.ListReleases(IncludeStable)
+	assert.Error(t, err)
+	assert.Nil(t, releaseList)
+}
+
+func TestCachedSource_FileURL(t *testing.T) {
+	sut := &CachedSource{Source: OfficialSource()}
+	assert.Equal(t, "https://golang.org/dl/go1.15.2.src.tar.gz", sut.FileURL("go1.15.2.src.tar.gz"))
+}
+
+type failingSource struct{}
+
+func (failingSource) ListReleases(ReleaseType) (Collection, error) {
+	return nil, errors.New("failure")
+}
+
+func (failingSource) FileURL(fileName string) string {
+	return fileName
+}
+
+func TestCachedSource_ListReleases_OfflineStableFromAll(t *testing.T) {
+	cacheDirectory := t.TempDir()
+	sut := &CachedSource{
+		Source:    failingSource{},
+		Directory: cacheDirectory,
+		Offline:   true,
+	}
+
+	require.NoError(t, writeCachedReleaseList(sut.cacheFile(IncludeAll), &cachedReleaseList{
+		FetchedAt: time.Now(),
+		Releases:  Collection{{Version: "go1.15.2", Stable: true}, {Version: "go1.16rc1"}},
+	}))
+
+	releaseList, err := sut.ListReleases(IncludeStable)
+	assert.NoError(t, err)
+	assert.Len(t, releaseList, 1)
+	assert.Equal(t, "go1.15.2", releaseList[0].Version)
+}
+
+func TestCachedSource_ListReleases_WithSwitchedSource(t *testing.T) {
+	releaseFile := filepath.Join(t.TempDir(), "releases.json")
+	require.NoError(t, ioutil.WriteFile(releaseFile, []byte(releaseListJSON), 0600))
+
+	cacheDirectory := t.TempDir()
+	sut := &CachedSource{
+		Source:    &FileSource{Path: releaseFile},
+		Directory: cacheDirectory,
+		TTL:       time.Hour,
+	}
+
+	releaseList, err := sut.ListReleases(IncludeAll)
+	assert.NoError(t, err)
+	assert.Len(t, releaseList, 2)
+
+	otherSut := &CachedSource{
+		Source:    &FileSource{Path: filepath.Join(t.TempDir(), "missing.json")},
+		Directory: cacheDirectory,
+		TTL:       time.Hour,
+	}
+	assert.NotEqual(t, sut.cacheFile(IncludeAll), otherSut.cacheFile(IncludeAll))
+
+	releaseList, err = otherSut.ListReleases(IncludeAll)
+	assert.Error(t, err)
+	assert.Nil(t, releaseList)
+}
+
+func TestCachedSource_ListReleases_WithUnexpectedNotModified(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		_, _ = w.Write([]byte(releaseListJSON))
+	}))
+	t.Cleanup(server.Close)
+
+	sut := &CachedSource{
+		Source:    &MirrorSource{BaseURL: server.URL},
+		Directory: t.TempDir(),
+		TTL:       time.Hour,
+	}
+	require.NoError(t, writeCachedReleaseList(sut.cacheFile(IncludeAll), &cachedReleaseList{
+		FetchedAt:  time.Now().Add(-24 * time.Hour),
+		Validators: Validators{ETag: `"v1"`},
+	}))
+
+	releaseList, err := sut.ListReleases(IncludeAll)
+	assert.NoError(t, err)
+	assert.Len(t, releaseList, 2)
+	assert.Equal(t, 2, requests)
+}
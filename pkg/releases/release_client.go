@@ -1,20 +1,16 @@
 package releases
 
 import (
-	"fmt"
 	"sort"
+	"sync"
 
 	"github.com/hashicorp/go-version"
-
-	"github.com/jangraefen/go-man/internal/httputil"
 )
 
 // The ReleaseType type is a string that describes what kind of release types should be returned by a release list.
 type ReleaseType string
 
 const (
-	releaseListURLTemplate = "https://golang.org/dl/?mode=json&include=%s"
-
 	// IncludeAll is the release type that will include each and every release of Go that was ever distributed publicly.
 	IncludeAll = ReleaseType("all")
 	// IncludeStable is the release type that will include each release that is currently considered stable.
@@ -24,6 +20,8 @@ const (
 var (
 	// ReleaseListCache is a map that caches the last fetched release list. Visible mostly for testing.
 	ReleaseListCache = map[ReleaseType]Collection{}
+
+	releaseListMutex sync.Mutex
 )
 
 // SelectReleaseType is a function that returns the release type that matches the input parameters best.
@@ -37,13 +35,16 @@ func SelectReleaseType(unstable bool) ReleaseType {
 	return IncludeStable
 }
 
-// ListAll is a function that retrieves a list of all Golang releases from the official website.
-// This list is retrieved by querying a JSON endpoint that is provided by the official Golang website. If the endpoint
-// responds with any other status code than 200, an error is returned.
+// ListAll is a function that retrieves a list of all Golang releases from the configured release source.
+// By default, this list is retrieved by querying a JSON endpoint that is provided by the official Golang website. If the
+// endpoint responds with any other status code than 200, an error is returned.
 func ListAll(releaseType ReleaseType) (Collection, error) {
+	releaseListMutex.Lock()
+	defer releaseListMutex.Unlock()
+
 	if _, ok := ReleaseListCache[releaseType]; !ok {
-		newReleaseList := Collection{}
-		if err := httputil.GetJSON(fmt.Sprintf(releaseListURLTemplate, releaseType), &newReleaseList); err != nil {
+		newReleaseList, err := Source.ListReleases(releaseType)
+		if err != nil {
 			return nil, err
 		}
 
@@ -82,3 +83,24 @@ func GetForVersion(releaseType ReleaseType, version *version.Version) (*Release,
 
 	return nil, false, nil
 }
+
+// GetForConstraints is a function that returns the highest Golang release, that satisfies the given version constraints.
+// A list of releases is retrieved, honoring the given release type as a filter, and then scanned for the highest release
+// that satisfies all constraints. If no such release can be found, an empty release object is returned and the boolean
+// return value will be set to false.
+func GetForConstraints(releaseType ReleaseType, constraints version.Constraints) (*Release, bool, error) {
+	releases, err := ListAll(releaseType)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var found *Release
+	for _, release := range releases {
+		if constraints.Check(release.GetVersionNumber()) &&
+			(found == nil || release.GetVersionNumber().GreaterThan(found.GetVersionNumber())) {
+			found = release
+		}
+	}
+
+	return found, found != nil, nil
+}
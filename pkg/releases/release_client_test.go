@@ -7,6 +7,7 @@ import (
 
 	"github.com/hashicorp/go-version"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/jangraefen/go-man/internal/httputil"
 )
@@ -106,3 +107,40 @@ func TestGetForVersion(t *testing.T) {
 	assert.False(t, exists)
 	assert.Nil(t, release)
 }
+
+func TestGetForConstraints(t *testing.T) {
+	t.Cleanup(func() {
+		delete(ReleaseListCache, IncludeAll)
+	})
+
+	ReleaseListCache[IncludeAll] = Collection{
+		{Version: "go1.16rc1"},
+		{Version: "go1.15.2"},
+		{Version: "go1.15.10"},
+		{Version: "go1.14.9"},
+	}
+
+	release, exists, err := GetForConstraints(IncludeAll, mustConstraints(t, ">= 1.14, < 1.16"))
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, "go1.15.10", release.Version)
+
+	release, exists, err = GetForConstraints(IncludeAll, mustConstraints(t, "< 1.15"))
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, "go1.14.9", release.Version)
+
+	release, exists, err = GetForConstraints(IncludeAll, mustConstraints(t, ">= 1.17"))
+	assert.NoError(t, err)
+	assert.False(t, exists)
+	assert.Nil(t, release)
+}
+
+func mustConstraints(t *testing.T, expression string) version.Constraints {
+	t.Helper()
+
+	constraints, err := version.NewConstraint(expression)
+	require.NoError(t, err)
+
+	return constraints
+}
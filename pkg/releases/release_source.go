@@ -0,0 +1,153 @@
+package releases
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jangraefen/go-man/internal/httputil"
+)
+
+const (
+	// OfficialBaseURL is the base URL of the official Golang website, where the release list and release files are served.
+	OfficialBaseURL = "https://golang.org/dl/"
+
+	releaseListQueryTemplate = "?mode=json&include=%s"
+)
+
+var (
+	// Source holds the release source that releases are listed and downloaded from.
+	// By default, the official Golang website is used, but this can be changed if needed.
+	Source ReleaseSource = OfficialSource()
+)
+
+// ReleaseSource is an interface for the origins that Golang releases can be obtained from.
+type ReleaseSource interface {
+	// ListReleases returns all releases of the given release type.
+	ListReleases(releaseType ReleaseType) (Collection, error)
+	// FileURL returns the URL, where a release file with the given name can be downloaded from.
+	FileURL(fileName string) string
+}
+
+// Validators is an alias for the validators of an HTTP response, which allow to check if a release list was modified since.
+type Validators = httputil.Validators
+
+// RevalidatingSource is an interface for release sources that can check if a previously listed release list was modified.
+type RevalidatingSource interface {
+	ReleaseSource
+	// ListReleasesIfModified returns all releases of the given release type and their validators, if the release list was
+	// modified since it was listed with the given validators. Otherwise, false is returned.
+	ListReleasesIfModified(releaseType ReleaseType, validators Validators) (Collection, Validators, bool, error)
+}
+
+// OfficialSource is a function that returns the release source for the official Golang website.
+func OfficialSource() *MirrorSource {
+	return &MirrorSource{BaseURL: OfficialBaseURL}
+}
+
+// MirrorSource is a release source that serves the release list and release files the same way as the official website.
+// This is the case for the official website itself and its mirrors, like https://go.dev/dl/ or https://golang.google.cn/dl/,
+// but also for proxies that forward requests to one of these, like a remote repository of a corporate artifact store.
+type MirrorSource struct {
+	// The base URL that the release list is served at and that file names are appended to, to obtain their download URL.
+	BaseURL string
+	// An optional base URL that overrides where the release files are downloaded from. This is useful for mirrors that only
+	// host release files, but not the release list.
+	FileBaseURL string
+}
+
+// ListReleases is a function that retrieves the release list from a JSON endpoint below the base URL.
+// If the endpoint responds with any other status code than 200, an error is returned.
+func (s *MirrorSource) ListReleases(releaseType ReleaseType) (Collection, error) {
+	releaseList, _, modified, err := s.ListReleasesIfModified(releaseType, Validators{})
+	if err == nil && !modified {
+		return nil, fmt.Errorf("release list of %s was reported as not modified, although it was never retrieved", s.BaseURL)
+	}
+
+	return releaseList, err
+}
+
+// ListReleasesIfModified is a function that retrieves the release list with a conditional request.
+// The ETag and Last-Modified headers of the previous response are used to ask the endpoint if the release list changed.
+func (s *MirrorSource) ListReleasesIfModified(
+	releaseType ReleaseType,
+	validators Validators,
+) (Collection, Validators, bool, error) {
+	releaseList := Collection{}
+	listURL := withTrailingSlash(s.BaseURL) + fmt.Sprintf(releaseListQueryTemplate, releaseType)
+
+	responseValidators, modified, err := httputil.GetJSONIfModified(listURL, validators, &releaseList)
+	if err != nil || !modified {
+		return nil, validators, false, err
+	}
+
+	return releaseList, responseValidators, true, nil
+}
+
+// FileURL is a function that returns the URL below the file base URL, or the base URL if none is set.
+func (s *MirrorSource) FileURL(fileName string) string {
+	if s.FileBaseURL != "" {
+		return withTrailingSlash(s.FileBaseURL) + fileName
+	}
+
+	return withTrailingSlash(s.BaseURL) + fileName
+}
+
+// FileSource is a release source that reads the release list from a local JSON file.
+// The file has to have the same structure as the release list of the official website, when it is queried for all releases.
+// This allows hosts without access to the official website to use a release list that was obtained elsewhere.
+type FileSource struct {
+	// The path of the JSON file that contains the release list.
+	Path string
+	// The base URL that release files are downloaded from. If not set, the official website is used.
+	FileBaseURL string
+}
+
+// ListReleases is a function that reads the release list from the JSON file.
+// If only stable releases are requested, all releases that are not marked as stable are filtered out.
+func (s *FileSource) ListReleases(releaseType ReleaseType) (Collection, error) {
+	file, err := os.Open(s.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	releaseList := Collection{}
+	if err := json.NewDecoder(file).Decode(&releaseList); err != nil {
+		return nil, fmt.Errorf("could not read release list %s: %w", s.Path, err)
+	}
+
+	if releaseType == IncludeAll {
+		return releaseList, nil
+	}
+
+	return filterStable(releaseList), nil
+}
+
+// FileURL is a function that returns the URL below the file base URL, or the official website if none is set.
+func (s *FileSource) FileURL(fileName string) string {
+	if s.FileBaseURL != "" {
+		return withTrailingSlash(s.FileBaseURL) + fileName
+	}
+
+	return OfficialBaseURL + fileName
+}
+
+func filterStable(releaseList Collection) Collection {
+	stableReleases := Collection{}
+	for _, release := range releaseList {
+		if release.Stable {
+			stableReleases = append(stableReleases, release)
+		}
+	}
+
+	return stableReleases
+}
+
+func withTrailingSlash(url string) string {
+	return strings.TrimSuffix(url, "/") + "/"
+}
@@ -0,0 +1,110 @@
+package releases
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jangraefen/go-man/internal/httputil"
+)
+
+const releaseListJSON = `[
+	{"version": "go1.15.2", "stable": true, "files": [{"filename": "go1.15.2.linux-amd64.tar.gz", "kind": "archive"}]},
+	{"version": "go1.16rc1", "stable": false, "files": []}
+]`
+
+func TestMirrorSource_ListReleases(t *testing.T) {
+	t.Cleanup(func() {
+		httputil.Client = http.DefaultClient
+	})
+
+	var requestedURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedURL = r.URL.String()
+		_, _ = w.Write([]byte(releaseListJSON))
+	}))
+	t.Cleanup(server.Close)
+
+	sut := &MirrorSource{BaseURL: server.URL + "/dl"}
+
+	releaseList, err := sut.ListReleases(IncludeAll)
+	assert.NoError(t, err)
+	assert.Len(t, releaseList, 2)
+	assert.Equal(t, "/dl/?mode=json&include=all", requestedURL)
+
+	httputil.Client = httputil.StaticResponseClient(0, nil, errors.New("failure"))
+
+	releaseList, err = sut.ListReleases(IncludeAll)
+	assert.Error(t, err)
+	assert.Nil(t, releaseList)
+}
+
+func TestMirrorSource_FileURL(t *testing.T) {
+	sut := OfficialSource()
+	assert.Equal(t, "https://golang.org/dl/go1.15.2.src.tar.gz", sut.FileURL("go1.15.2.src.tar.gz"))
+
+	sut = &MirrorSource{BaseURL: "https://go.dev/dl"}
+	assert.Equal(t, "https://go.dev/dl/go1.15.2.src.tar.gz", sut.FileURL("go1.15.2.src.tar.gz"))
+
+	sut.FileBaseURL = "https://mirror.example.org/golang/"
+	assert.Equal(t, "https://mirror.example.org/golang/go1.15.2.src.tar.gz", sut.FileURL("go1.15.2.src.tar.gz"))
+}
+
+func TestFileSource_ListReleases(t *testing.T) {
+	releaseFile := filepath.Join(t.TempDir(), "releases.json")
+	sut := &FileSource{Path: releaseFile}
+
+	releaseList, err := sut.ListReleases(IncludeAll)
+	assert.Error(t, err)
+	assert.Nil(t, releaseList)
+
+	require.NoError(t, ioutil.WriteFile(releaseFile, []byte("not json"), 0600))
+
+	releaseList, err = sut.ListReleases(IncludeAll)
+	assert.Error(t, err)
+	assert.Nil(t, releaseList)
+
+	require.NoError(t, ioutil.WriteFile(releaseFile, []byte(releaseListJSON), 0600))
+
+	releaseList, err = sut.ListReleases(IncludeAll)
+	assert.NoError(t, err)
+	assert.Len(t, releaseList, 2)
+
+	releaseList, err = sut.ListReleases(IncludeStable)
+	assert.NoError(t, err)
+	assert.Len(t, releaseList, 1)
+	assert.Equal(t, "go1.15.2", releaseList[0].Version)
+	assert.Equal(t, "go1.15.2.linux-amd64.tar.gz", releaseList[0].Files[0].Filename)
+}
+
+func TestFileSource_FileURL(t *testing.T) {
+	sut := &FileSource{Path: "releases.json"}
+	assert.Equal(t, "https://golang.org/dl/go1.15.2.src.tar.gz", sut.FileURL("go1.15.2.src.tar.gz"))
+
+	sut.FileBaseURL = "https://artifacts.example.org/go"
+	assert.Equal(t, "https://artifacts.example.org/go/go1.15.2.src.tar.gz", sut.FileURL("go1.15.2.src.tar.gz"))
+}
+
+func TestListAll_WithSource(t *testing.T) {
+	releaseFile := filepath.Join(t.TempDir(), "releases.json")
+	require.NoError(t, ioutil.WriteFile(releaseFile, []byte(releaseListJSON), 0600))
+
+	t.Cleanup(func() {
+		Source = OfficialSource()
+		delete(ReleaseListCache, IncludeAll)
+	})
+
+	Source = &FileSource{Path: releaseFile, FileBaseURL: "https://artifacts.example.org/go"}
+	delete(ReleaseListCache, IncludeAll)
+
+	releaseList, err := ListAll(IncludeAll)
+	assert.NoError(t, err)
+	assert.Len(t, releaseList, 2)
+	assert.Equal(t, "https://artifacts.example.org/go/go1.15.2.linux-amd64.tar.gz", releaseList[0].Files[0].GetURL())
+}
@@ -8,45 +8,38 @@ import (
 // Printf is a function that logs any string to system out.
 // It provides the same formatting as the fmt package does.
 func (t Task) Printf(format string, args ...interface{}) {
-	_, _ = fmt.Fprintf(t.Output, t.logTemplate(format, true), args...)
+	t.reporter().Message(t.indention, fmt.Sprintf(format, args...))
 }
 
-// Fatalf is a function that logs any string to system err and causes the application to exit.
+// Errorf is a function that logs any string to system err. Deciding whether the application continues or exits after an
+// error is left to the caller, so it is safe to use in library code.
 // It provides the same formatting as the fmt package does.
+func (t Task) Errorf(format string, args ...interface{}) {
+	t.reporter().Error(t.indention, fmt.Sprintf(format, args...))
+}
+
+// Fatalf is a function that logs any string to system err and causes the application to exit with the ErrorExitCode.
+// It provides the same formatting as the fmt package does.
+//
+// Deprecated: Library code must not exit the application. Report the error with Errorf and return it instead.
 func (t Task) Fatalf(format string, args ...interface{}) {
-	_, _ = fmt.Fprintf(t.Error, t.logTemplate(format, true), args...)
+	t.Errorf(format, args...)
 	os.Exit(t.ErrorExitCode)
 }
 
 // FatalOnError is a function that logs any error to system err and causes the application to exit, if a condition matches.
+//
+// Deprecated: Library code must not exit the application. Report the error with Errorf and return it instead.
 func (t Task) FatalOnError(err error) {
 	t.FatalIff(err != nil, "%s", err)
 }
 
 // FatalIff is a function that logs any string to system err and causes the application to exit, if a condition matches.
 // It provides the same formatting as the fmt package does.
+//
+// Deprecated: Library code must not exit the application. Report the error with Errorf and return it instead.
 func (t Task) FatalIff(condition bool, format string, args ...interface{}) {
 	if condition {
 		t.Fatalf(format, args...)
 	}
 }
-
-func (t Task) logTemplate(format string, newline bool) string {
-	endLine := ""
-	if newline {
-		endLine = "\n"
-	}
-
-	switch {
-	case t.indention == 0:
-		return format + endLine
-	case t.indention == 1:
-		return fmt.Sprintf("-> %s%s", format, endLine)
-	default:
-		indentionString := " "
-		for i := uint(1); i < t.indention; i++ {
-			indentionString += "  "
-		}
-		return fmt.Sprintf("%s-> %s%s", indentionString, format, endLine)
-	}
-}
@@ -1,4 +1,4 @@
 // Package tasks contains an abstraction of tasks and their state.
 // The task abstraction breaks the coupling between functions that perform a composite task and feedback to a user about the
-// progress of each step of the composite task.
+// progress of each step of the composite task. How that feedback is presented is decided by a Reporter.
 package tasks
@@ -0,0 +1,91 @@
+package tasks
+
+import (
+	"fmt"
+	"time"
+)
+
+var (
+	// The interval in which the progress is passed to the reporter.
+	updateInterval = 200 * time.Millisecond
+)
+
+// Progress is a struct that keeps track of a workload, that processes a known amount of bytes.
+// It is created by TrackProgress and passes the progress to the reporter of the task periodically.
+type Progress struct {
+	step  ReportedStep
+	total int64
+
+	started  time.Time
+	updated  time.Time
+	baseline int64
+	done     int64
+}
+
+// TrackProgress is a function that logs the tracked status of a given workload function, like Track does. Additionally,
+// the workload reports how many of the total bytes are processed, which is rendered together with the rate and the remaining
+// time. A total of zero or less means, that the amount of bytes is not known in advance.
+func (t Task) TrackProgress(description string, total int64, workload func(progress *Progress) error) error {
+	progress := &Progress{
+		step:     t.reporter().Begin(t.indention, description),
+		total:    total,
+		started:  time.Now(),
+		baseline: -1,
+	}
+	progress.updated = progress.started
+	started := progress.started
+
+	err := workload(progress)
+	progress.step.End(err, time.Since(started))
+	return err
+}
+
+// Report is a function that reports the amount of bytes, that are processed so far.
+// The amount may decrease, if the workload has to start over.
+func (p *Progress) Report(done int64) {
+	now := time.Now()
+	if p.baseline < 0 || done < p.baseline {
+		p.baseline, p.started = done, now
+	}
+	p.done = done
+
+	if now.Sub(p.updated) < updateInterval {
+		return
+	}
+
+	p.updated = now
+	p.step.Progress(p.status(now))
+}
+
+func (p *Progress) status(now time.Time) ProgressStatus {
+	done := p.done
+	if p.total > 0 && done > p.total {
+		done = p.total
+	}
+
+	status := ProgressStatus{Done: done, Total: p.total, Remaining: -1}
+	if elapsed := now.Sub(p.started).Seconds(); elapsed > 0 {
+		status.Rate = float64(done-p.baseline) / elapsed
+	}
+	if p.total > 0 && status.Rate > 0 {
+		status.Remaining = time.Duration(float64(p.total-done) / status.Rate * float64(time.Second))
+	}
+
+	return status
+}
+
+// FormatSize is a function that formats an amount of bytes as a human readable size.
+func FormatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	divisor, exponent := int64(unit), 0
+	for quotient := size / unit; quotient >= unit; quotient /= unit {
+		divisor *= unit
+		exponent++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(divisor), "KMGTPE"[exponent])
+}
@@ -0,0 +1,176 @@
+package tasks
+
+import (
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// NoColorVariable is the name of the environment variable, that disables colored output if it is set to any value.
+	NoColorVariable = "NO_COLOR"
+)
+
+// Reporter is an interface for presenting the output of tasks, either to a user or to another program.
+// Each output is reported together with the depth of the task that produced it, so it can be arranged as a tree.
+type Reporter interface {
+	// Message is a function that reports a message of a task.
+	Message(depth uint, message string)
+	// Error is a function that reports the message of an error.
+	Error(depth uint, message string)
+	// Begin is a function that reports the start of a tracked workload. The returned ReportedStep receives the progress and
+	// the result of the workload.
+	Begin(depth uint, description string) ReportedStep
+}
+
+// ReportedStep is an interface for presenting the progress and the result of a single tracked workload.
+type ReportedStep interface {
+	// Progress is a function that reports how far the workload has progressed. It is called at most once per update interval.
+	Progress(status ProgressStatus)
+	// End is a function that reports the result of the workload, together with the time it took. A nil error means, that the
+	// workload succeeded.
+	End(err error, elapsed time.Duration)
+}
+
+// ProgressStatus is a struct that describes how far a workload, that processes a known amount of bytes, has progressed.
+type ProgressStatus struct {
+	// The amount of bytes that are processed so far.
+	Done int64
+	// The total amount of bytes. Zero or less, if the amount is not known in advance.
+	Total int64
+	// The amount of bytes that are processed per second.
+	Rate float64
+	// The time that the workload presumably needs to finish. Negative, if it cannot be estimated.
+	Remaining time.Duration
+}
+
+// NewReporter is a constructor for the default Reporter, that is used if a Task has none.
+// If the output is a terminal and colors are not disabled by the NO_COLOR environment variable, the output is reported as a
+// colored tree like NewTreeReporter does. Otherwise, it is reported like NewPlainReporter does.
+func NewReporter(output, errorOutput io.Writer) Reporter {
+	if !colorsEnabled(output) {
+		return NewPlainReporter(output, errorOutput)
+	}
+
+	return NewTreeReporter(output, errorOutput)
+}
+
+// NewTreeReporter is a constructor for a Reporter, that arranges the output as a colored tree. The progress of a workload is
+// rendered as a live bar, if the output is a terminal.
+func NewTreeReporter(output, errorOutput io.Writer) Reporter {
+	return &textReporter{output: output, errorOutput: errorOutput, colored: true, live: isTerminal(output)}
+}
+
+// NewPlainReporter is a constructor for a Reporter, that arranges the output as a tree without any colors or control
+// characters. The progress of a workload is printed as a separate line periodically, which suits log files.
+func NewPlainReporter(output, errorOutput io.Writer) Reporter {
+	return &textReporter{output: output, errorOutput: errorOutput}
+}
+
+// NewVerboseReporter is a constructor for a Reporter, that reports like NewReporter does, but additionally reports the
+// time that each tracked workload took.
+func NewVerboseReporter(output, errorOutput io.Writer) Reporter {
+	colored := colorsEnabled(output)
+	return &textReporter{output: output, errorOutput: errorOutput, colored: colored, live: colored, durations: true}
+}
+
+// NewQuietReporter is a constructor for a Reporter, that only reports the messages of errors.
+func NewQuietReporter(errorOutput io.Writer) Reporter {
+	return &quietReporter{errorOutput: errorOutput}
+}
+
+func colorsEnabled(output io.Writer) bool {
+	return os.Getenv(NoColorVariable) == "" && isTerminal(output)
+}
+
+func isTerminal(output io.Writer) bool {
+	file, ok := output.(*os.File)
+	if !ok {
+		return false
+	}
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return false
+	}
+
+	return fileInfo.Mode()&os.ModeCharDevice != 0
+}
+
+// quietReporter is a reporter that drops everything except errors.
+type quietReporter struct {
+	errorOutput io.Writer
+}
+
+func (r *quietReporter) Message(uint, string) {}
+
+func (r *quietReporter) Error(_ uint, message string) {
+	_, _ = io.WriteString(r.errorOutput, message+"\n")
+}
+
+func (r *quietReporter) Begin(uint, string) ReportedStep {
+	return quietStep{}
+}
+
+type quietStep struct{}
+
+func (quietStep) Progress(ProgressStatus) {}
+
+func (quietStep) End(error, time.Duration) {}
+
+// concurrentMutex serializes the output of all concurrent reporters, so that their lines are never torn apart.
+var concurrentMutex sync.Mutex
+
+// concurrentReporter is a reporter that reports everything as complete lines, that are prefixed with the name of the task.
+// This way, the output of multiple concurrent tasks can be interleaved, while still being told apart.
+type concurrentReporter struct {
+	target Reporter
+	prefix string
+}
+
+func (r *concurrentReporter) Message(depth uint, message string) {
+	concurrentMutex.Lock()
+	defer concurrentMutex.Unlock()
+
+	r.target.Message(depth, r.prefix+message)
+}
+
+func (r *concurrentReporter) Error(depth uint, message string) {
+	concurrentMutex.Lock()
+	defer concurrentMutex.Unlock()
+
+	r.target.Error(depth, r.prefix+message)
+}
+
+func (r *concurrentReporter) Begin(depth uint, description string) ReportedStep {
+	return &concurrentStep{reporter: r, depth: depth, description: r.prefix + description, reported: time.Now()}
+}
+
+// concurrentStep is a step whose progress is reported as periodic messages, since a live bar cannot be shared by multiple
+// workloads. The workload itself is reported once it ends, so that its description and its result share a single line.
+type concurrentStep struct {
+	reporter    *concurrentReporter
+	depth       uint
+	description string
+	reported    time.Time
+}
+
+func (s *concurrentStep) Progress(status ProgressStatus) {
+	if time.Since(s.reported) < periodicInterval {
+		return
+	}
+	s.reported = time.Now()
+
+	concurrentMutex.Lock()
+	defer concurrentMutex.Unlock()
+
+	s.reporter.target.Message(s.depth+1, s.description+": "+formatStatus(status, false))
+}
+
+func (s *concurrentStep) End(err error, elapsed time.Duration) {
+	concurrentMutex.Lock()
+	defer concurrentMutex.Unlock()
+
+	s.reporter.target.Begin(s.depth, s.description).End(err, elapsed)
+}
@@ -0,0 +1,94 @@
+package tasks
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// The kinds of events, that are reported by a reporter created by NewJSONReporter.
+const (
+	MessageEvent  = "message"
+	ErrorEvent    = "error"
+	BeginEvent    = "begin"
+	ProgressEvent = "progress"
+	EndEvent      = "end"
+)
+
+// Event is a struct that describes a single event, that is reported by a reporter created by NewJSONReporter.
+type Event struct {
+	// The time at which the event was reported.
+	Time time.Time `json:"time"`
+	// The kind of the event, like MessageEvent or BeginEvent.
+	Kind string `json:"kind"`
+	// The depth of the task that reported the event.
+	Depth uint `json:"depth"`
+	// The message of a task or the description of a workload.
+	Message string `json:"message"`
+	// The amount of bytes that a workload processed so far. Only set for progress events.
+	Done int64 `json:"done,omitempty"`
+	// The total amount of bytes that a workload processes. Only set for progress events, if the total amount is known.
+	Total int64 `json:"total,omitempty"`
+	// The time in seconds that a workload took. Only set for end events.
+	Elapsed float64 `json:"elapsed,omitempty"`
+	// The error that a workload failed with. Only set for end events of failed workloads.
+	Error string `json:"error,omitempty"`
+}
+
+// NewJSONReporter is a constructor for a Reporter, that writes each output as a single line JSON encoded Event.
+func NewJSONReporter(output io.Writer) Reporter {
+	return &jsonReporter{encoder: json.NewEncoder(output)}
+}
+
+// jsonReporter is a reporter that writes a stream of JSON encoded events.
+type jsonReporter struct {
+	mutex   sync.Mutex
+	encoder *json.Encoder
+}
+
+func (r *jsonReporter) Message(depth uint, message string) {
+	r.report(Event{Kind: MessageEvent, Depth: depth, Message: message})
+}
+
+func (r *jsonReporter) Error(depth uint, message string) {
+	r.report(Event{Kind: ErrorEvent, Depth: depth, Message: message})
+}
+
+func (r *jsonReporter) Begin(depth uint, description string) ReportedStep {
+	r.report(Event{Kind: BeginEvent, Depth: depth, Message: description})
+	return &jsonStep{reporter: r, depth: depth, description: description}
+}
+
+func (r *jsonReporter) report(event Event) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	event.Time = time.Now().UTC()
+	_ = r.encoder.Encode(event)
+}
+
+type jsonStep struct {
+	reporter    *jsonReporter
+	depth       uint
+	description string
+}
+
+func (s *jsonStep) Progress(status ProgressStatus) {
+	s.reporter.report(Event{
+		Kind:    ProgressEvent,
+		Depth:   s.depth,
+		Message: s.description,
+		Done:    status.Done,
+		Total:   status.Total,
+	})
+}
+
+func (s *jsonStep) End(err error, elapsed time.Duration) {
+	event := Event{Kind: EndEvent, Depth: s.depth, Message: s.description, Elapsed: elapsed.Seconds()}
+	if err != nil {
+		event.Error = err.Error()
+	}
+
+	s.reporter.report(event)
+}
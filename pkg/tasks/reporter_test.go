@@ -0,0 +1,298 @@
+package tasks
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReporter(t *testing.T) {
+	output := &bytes.Buffer{}
+
+	reporter, ok := NewReporter(output, output).(*textReporter)
+	require.True(t, ok)
+	assert.False(t, reporter.colored)
+	assert.False(t, reporter.live)
+	assert.False(t, reporter.durations)
+
+	reporter, ok = NewVerboseReporter(output, output).(*textReporter)
+	require.True(t, ok)
+	assert.False(t, reporter.colored)
+	assert.False(t, reporter.live)
+	assert.True(t, reporter.durations)
+
+	reporter, ok = NewTreeReporter(output, output).(*textReporter)
+	require.True(t, ok)
+	assert.True(t, reporter.colored)
+	assert.False(t, reporter.live)
+}
+
+func TestNewReporter_WithTerminal(t *testing.T) {
+	t.Cleanup(func() {
+		_ = os.Unsetenv(NoColorVariable)
+	})
+
+	// A terminal is only available, if the tests are run from one.
+	terminal, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+	if err != nil {
+		t.Skip("no terminal available")
+	}
+	t.Cleanup(func() {
+		_ = terminal.Close()
+	})
+
+	reporter, ok := NewReporter(terminal, terminal).(*textReporter)
+	require.True(t, ok)
+	assert.True(t, reporter.colored)
+	assert.True(t, reporter.live)
+
+	require.NoError(t, os.Setenv(NoColorVariable, "1"))
+
+	reporter, ok = NewReporter(terminal, terminal).(*textReporter)
+	require.True(t, ok)
+	assert.False(t, reporter.colored)
+	assert.False(t, reporter.live)
+}
+
+func TestColorsEnabled(t *testing.T) {
+	file, err := os.Create(filepath.Join(t.TempDir(), "output.log"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = file.Close()
+	})
+
+	assert.False(t, isTerminal(&bytes.Buffer{}))
+	assert.False(t, isTerminal(file))
+	assert.False(t, colorsEnabled(&bytes.Buffer{}))
+	assert.False(t, colorsEnabled(file))
+}
+
+func TestTextReporter(t *testing.T) {
+	output := &bytes.Buffer{}
+	errorOutput := &bytes.Buffer{}
+	sut := &textReporter{output: output, errorOutput: errorOutput}
+
+	sut.Message(0, "Installing")
+	sut.Message(1, "Detected version")
+	sut.Message(2, "Details")
+	sut.Error(1, "failure")
+	sut.Begin(1, "Extracting").End(nil, time.Second)
+	sut.Begin(1, "Verifying").End(errors.New("mismatch"), time.Second)
+
+	assert.Equal(
+		t,
+		"Installing\n-> Detected version\n   -> Details\n-> Extracting... Done\n-> Verifying... Failed\n",
+		output.String(),
+	)
+	assert.Equal(t, "-> failure\n", errorOutput.String())
+
+	output.Reset()
+	sut.durations = true
+	sut.Begin(0, "Downloading").End(nil, 1500*time.Millisecond)
+	sut.Begin(0, "Linking").End(nil, 1500*time.Microsecond)
+	assert.Equal(t, "Downloading... Done (1.5s)\nLinking... Done (2ms)\n", output.String())
+}
+
+func TestTextStep_Progress_WithLiveBar(t *testing.T) {
+	output := &bytes.Buffer{}
+	sut := &textReporter{output: output, live: true}
+
+	step := sut.Begin(1, "Downloading")
+	step.Progress(ProgressStatus{Done: 512, Total: 1024, Rate: 2048, Remaining: 1234 * time.Millisecond})
+
+	firstLine := "-> Downloading... [==========          ]  50% 512 B/1.0 KiB 2.0 KiB/s, 1s left"
+	assert.Equal(t, "-> Downloading...\r"+firstLine, output.String())
+
+	// A shorter line has to overwrite the remainders of the previous one.
+	output.Reset()
+	step.Progress(ProgressStatus{Done: 1024, Total: 1024, Rate: 1, Remaining: 0})
+
+	secondLine := "-> Downloading... [====================] 100% 1.0 KiB/1.0 KiB 1 B/s, 0s left"
+	assert.Equal(t, "\r"+secondLine+strings.Repeat(" ", len(firstLine)-len(secondLine)), output.String())
+
+	output.Reset()
+	step.End(nil, time.Second)
+
+	padding := strings.Repeat(" ", len(secondLine)-len("-> Downloading... Failed"))
+	assert.Equal(t, "\r-> Downloading... Done"+padding+"\n", output.String())
+}
+
+func TestTextStep_Progress_WithPeriodicLines(t *testing.T) {
+	interval := periodicInterval
+	t.Cleanup(func() {
+		periodicInterval = interval
+	})
+
+	output := &bytes.Buffer{}
+	sut := &textReporter{output: output}
+
+	periodicInterval = time.Hour
+	step := sut.Begin(0, "Downloading")
+	step.Progress(ProgressStatus{Done: 512, Total: 1024})
+	assert.Equal(t, "Downloading...", output.String())
+
+	periodicInterval = 0
+	step.Progress(ProgressStatus{Done: 512, Total: 1024, Rate: 2048, Remaining: -1})
+	step.Progress(ProgressStatus{Done: 2048, Rate: 2048, Remaining: -1})
+	step.End(errors.New("failure"), time.Second)
+
+	assert.Equal(
+		t,
+		"Downloading...\n"+
+			"-> 50% (512 B of 1.0 KiB) at 2.0 KiB/s, unknown time left\n"+
+			"-> 2.0 KiB at 2.0 KiB/s\n"+
+			"Downloading... Failed\n",
+		output.String(),
+	)
+}
+
+func TestFormatStatus(t *testing.T) {
+	testCases := []struct {
+		name     string
+		status   ProgressStatus
+		bar      bool
+		expected string
+	}{
+		{
+			name:     "unknown total",
+			status:   ProgressStatus{Done: 3 << 20, Rate: 1 << 20, Remaining: -1},
+			expected: "3.0 MiB at 1.0 MiB/s",
+		},
+		{
+			name:     "unknown remaining time",
+			status:   ProgressStatus{Done: 256, Total: 1024, Rate: 128, Remaining: -1},
+			expected: "25% (256 B of 1.0 KiB) at 128 B/s, unknown time left",
+		},
+		{
+			name:     "done exceeds total",
+			status:   ProgressStatus{Done: 4096, Total: 1024, Rate: 128, Remaining: 0},
+			expected: "100% (1.0 KiB of 1.0 KiB) at 128 B/s, 0s left",
+		},
+		{
+			name:     "bar",
+			status:   ProgressStatus{Done: 256, Total: 1024, Rate: 128, Remaining: 6 * time.Second},
+			bar:      true,
+			expected: "[=====               ]  25% 256 B/1.0 KiB 128 B/s, 6s left",
+		},
+		{
+			name:     "full bar",
+			status:   ProgressStatus{Done: 2048, Total: 1024, Rate: 128, Remaining: 0},
+			bar:      true,
+			expected: "[====================] 100% 1.0 KiB/1.0 KiB 128 B/s, 0s left",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, formatStatus(testCase.status, testCase.bar))
+		})
+	}
+}
+
+func TestJSONReporter(t *testing.T) {
+	output := &bytes.Buffer{}
+	sut := NewJSONReporter(output)
+
+	sut.Message(0, "Installing")
+	sut.Error(1, "warning")
+	step := sut.Begin(1, "Downloading")
+	step.Progress(ProgressStatus{Done: 512, Total: 1024})
+	step.End(errors.New("failure"), 2*time.Second)
+	sut.Begin(1, "Verifying").End(nil, time.Second)
+
+	// Each event has to be written as a single line, so that the stream can be consumed line by line.
+	var events []Event
+	for _, line := range strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n") {
+		event := Event{}
+		require.NoError(t, json.Unmarshal([]byte(line), &event))
+		assert.False(t, event.Time.IsZero())
+
+		event.Time = time.Time{}
+		events = append(events, event)
+	}
+
+	assert.Equal(t, []Event{
+		{Kind: MessageEvent, Depth: 0, Message: "Installing"},
+		{Kind: ErrorEvent, Depth: 1, Message: "warning"},
+		{Kind: BeginEvent, Depth: 1, Message: "Downloading"},
+		{Kind: ProgressEvent, Depth: 1, Message: "Downloading", Done: 512, Total: 1024},
+		{Kind: EndEvent, Depth: 1, Message: "Downloading", Elapsed: 2, Error: "failure"},
+		{Kind: BeginEvent, Depth: 1, Message: "Verifying"},
+		{Kind: EndEvent, Depth: 1, Message: "Verifying", Elapsed: 1},
+	}, events)
+}
+
+func TestQuietReporter(t *testing.T) {
+	errorOutput := &bytes.Buffer{}
+	sut := NewQuietReporter(errorOutput)
+
+	sut.Message(0, "Installing")
+	step := sut.Begin(1, "Downloading")
+	step.Progress(ProgressStatus{Done: 512, Total: 1024})
+	step.End(nil, time.Second)
+	sut.Error(1, "failure")
+
+	assert.Equal(t, "failure\n", errorOutput.String())
+}
+
+func TestTask_Concurrent(t *testing.T) {
+	interval := periodicInterval
+	t.Cleanup(func() {
+		periodicInterval = interval
+	})
+	periodicInterval = 0
+
+	output := &bytes.Buffer{}
+	task := &Task{Reporter: &textReporter{output: output, errorOutput: output}}
+
+	first := task.Concurrent("1.15.2")
+	second := task.Concurrent("1.14.9")
+
+	first.Printf("Installing")
+	firstStep := first.reporter().Begin(1, "Downloading")
+	second.Step().Printf("Detected version")
+	firstStep.Progress(ProgressStatus{Done: 512, Total: 1024, Rate: 512, Remaining: time.Second})
+	second.Errorf("failure")
+	firstStep.End(nil, time.Second)
+	assert.NoError(t, second.Track("Verifying", func() error { return nil }))
+
+	assert.Equal(
+		t,
+		"[1.15.2] Installing\n"+
+			"-> [1.14.9] Detected version\n"+
+			"   -> [1.15.2] Downloading: 50% (512 B of 1.0 KiB) at 512 B/s, 1s left\n"+
+			"[1.14.9] failure\n"+
+			"-> [1.15.2] Downloading... Done\n"+
+			"[1.14.9] Verifying... Done\n",
+		output.String(),
+	)
+}
+
+func TestTask_WithoutReporter(t *testing.T) {
+	output := &bytes.Buffer{}
+	task := Task{Output: output, Error: ioutil.Discard}
+
+	task.Printf("Installing %s", "1.15.2")
+	assert.NoError(t, task.Step().Track("Extracting", func() error { return nil }))
+
+	assert.Equal(t, "Installing 1.15.2\n-> Extracting... Done\n", output.String())
+}
+
+func TestTask_FatalIff_WithoutFailure(t *testing.T) {
+	output := &bytes.Buffer{}
+	task := Task{Output: output, Error: output, ErrorExitCode: 1}
+
+	task.FatalIff(false, "failure")
+	task.FatalOnError(nil)
+
+	assert.Empty(t, output.String())
+}
@@ -0,0 +1,192 @@
+package tasks
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/gookit/color"
+)
+
+const (
+	progressBarWidth = 20
+)
+
+var (
+	styleDone  = color.New(color.FgGreen, color.Bold).Render
+	styleError = color.New(color.FgRed, color.Bold).Render
+
+	// The interval in which a progress line is printed, if the progress is not rendered as a live bar.
+	periodicInterval = 5 * time.Second
+)
+
+// textReporter is a reporter that arranges the output as a tree of human readable lines.
+type textReporter struct {
+	output      io.Writer
+	errorOutput io.Writer
+	// Whether results are styled with colors.
+	colored bool
+	// Whether the progress is rendered as a live bar, instead of periodic lines.
+	live bool
+	// Whether the time that each workload took is reported.
+	durations bool
+}
+
+func (r *textReporter) Message(depth uint, message string) {
+	_, _ = fmt.Fprint(r.output, logTemplate(depth, message, true))
+}
+
+func (r *textReporter) Error(depth uint, message string) {
+	_, _ = fmt.Fprint(r.errorOutput, logTemplate(depth, message, true))
+}
+
+func (r *textReporter) Begin(depth uint, description string) ReportedStep {
+	_, _ = fmt.Fprint(r.output, logTemplate(depth, description+"...", false))
+	return &textStep{reporter: r, depth: depth, description: description, rendered: time.Now()}
+}
+
+func (r *textReporter) style(result string, failed bool) string {
+	if !r.colored {
+		return result
+	}
+	if failed {
+		return styleError(result)
+	}
+
+	return styleDone(result)
+}
+
+// textStep is a step that renders the progress and the result of a workload behind its description.
+type textStep struct {
+	reporter    *textReporter
+	depth       uint
+	description string
+
+	rendered time.Time
+	reported bool
+	width    int
+}
+
+func (s *textStep) Progress(status ProgressStatus) {
+	r := s.reporter
+	if r.live {
+		line := logTemplate(s.depth, fmt.Sprintf("%s... %s", s.description, formatStatus(status, true)), false)
+		_, _ = fmt.Fprintf(r.output, "\r%s%s", line, s.padding(len(line)))
+		s.width = len(line)
+		s.reported = true
+		return
+	}
+
+	now := time.Now()
+	if now.Sub(s.rendered) < periodicInterval {
+		return
+	}
+	s.rendered = now
+
+	if !s.reported {
+		_, _ = fmt.Fprintln(r.output)
+		s.reported = true
+	}
+	r.Message(s.depth+1, formatStatus(status, false))
+}
+
+func (s *textStep) End(err error, elapsed time.Duration) {
+	r := s.reporter
+
+	result := r.style(" Done", false)
+	if err != nil {
+		result = r.style(" Failed", true)
+	}
+	if r.durations {
+		result += fmt.Sprintf(" (%s)", formatDuration(elapsed))
+	}
+
+	if !s.reported {
+		_, _ = fmt.Fprintln(r.output, result)
+		return
+	}
+
+	// The description has to be repeated, since it was either overwritten by the live bar or scrolled away by progress lines.
+	line := logTemplate(s.depth, s.description+"...", false)
+	if r.live {
+		_, _ = fmt.Fprint(r.output, "\r")
+	}
+	_, _ = fmt.Fprintln(r.output, line+result+s.padding(len(line)+len(" Failed")))
+}
+
+func (s *textStep) padding(width int) string {
+	if !s.reporter.live || width >= s.width {
+		return ""
+	}
+
+	return strings.Repeat(" ", s.width-width)
+}
+
+func formatDuration(duration time.Duration) string {
+	if duration < time.Millisecond {
+		return duration.Round(time.Microsecond).String()
+	}
+
+	return duration.Round(time.Millisecond).String()
+}
+
+func formatStatus(status ProgressStatus, bar bool) string {
+	done := status.Done
+	if status.Total > 0 && done > status.Total {
+		done = status.Total
+	}
+
+	if status.Total <= 0 {
+		return fmt.Sprintf("%s at %s/s", FormatSize(done), FormatSize(int64(status.Rate)))
+	}
+
+	percentage := done * 100 / status.Total
+	remaining := "unknown time"
+	if status.Remaining >= 0 {
+		remaining = status.Remaining.Round(time.Second).String()
+	}
+
+	if bar {
+		filled := int(done * progressBarWidth / status.Total)
+		return fmt.Sprintf(
+			"[%s%s] %3d%% %s/%s %s/s, %s left",
+			strings.Repeat("=", filled),
+			strings.Repeat(" ", progressBarWidth-filled),
+			percentage,
+			FormatSize(done),
+			FormatSize(status.Total),
+			FormatSize(int64(status.Rate)),
+			remaining,
+		)
+	}
+
+	return fmt.Sprintf(
+		"%d%% (%s of %s) at %s/s, %s left",
+		percentage,
+		FormatSize(done),
+		FormatSize(status.Total),
+		FormatSize(int64(status.Rate)),
+		remaining,
+	)
+}
+
+func logTemplate(depth uint, message string, newline bool) string {
+	endLine := ""
+	if newline {
+		endLine = "\n"
+	}
+
+	switch {
+	case depth == 0:
+		return message + endLine
+	case depth == 1:
+		return fmt.Sprintf("-> %s%s", message, endLine)
+	default:
+		indentionString := " "
+		for i := uint(1); i < depth; i++ {
+			indentionString += "  "
+		}
+		return fmt.Sprintf("%s-> %s%s", indentionString, message, endLine)
+	}
+}
@@ -1,43 +1,54 @@
 package tasks
 
 import (
-	"fmt"
 	"io"
-
-	"github.com/gookit/color"
-)
-
-var (
-	styleDone  = color.New(color.FgGreen, color.Bold).Render
-	styleError = color.New(color.FgRed, color.Bold).Render
+	"time"
 )
 
 // The Task struct holds the necessary information to produce output for users that execute a multi-step process.
+// All output is passed to a Reporter, which decides how it is presented.
 type Task struct {
+	// The exit code that is used by the deprecated Fatalf, FatalIff and FatalOnError functions.
+	//
+	// Deprecated: Tasks are not meant to exit the application. Failures are returned as errors instead.
 	ErrorExitCode int
 	Output        io.Writer
 	Error         io.Writer
-	indention     uint
+	// The reporter that presents the output of the task. If nil, the output is reported to the Output and Error writers by a
+	// reporter that is chosen by NewReporter.
+	Reporter  Reporter
+	indention uint
 }
 
 // Step is a function that returns a sub-task for of the receiving Task.
 func (t Task) Step() *Task {
-	return &Task{
-		ErrorExitCode: t.ErrorExitCode,
-		Output:        t.Output,
-		Error:         t.Error,
-		indention:     t.indention + 1,
-	}
+	step := t
+	step.indention++
+	return &step
+}
+
+// Concurrent is a function that returns a copy of the receiving Task, that can run concurrently with other tasks. Its output
+// is reported as complete lines, that are prefixed with the given name, so that the output of all tasks can be told apart.
+func (t Task) Concurrent(name string) *Task {
+	concurrent := t
+	concurrent.Reporter = &concurrentReporter{target: t.reporter(), prefix: "[" + name + "] "}
+	return &concurrent
 }
 
 // Track is a function that logs the tracked status of a given workload function.
 func (t Task) Track(description string, workload func() error) error {
-	_, _ = fmt.Fprintf(t.Output, t.logTemplate("%s...", false), description)
-	if err := workload(); err != nil {
-		_, _ = fmt.Fprintln(t.Output, styleError(" Failed"))
-		return err
+	step := t.reporter().Begin(t.indention, description)
+	started := time.Now()
+
+	err := workload()
+	step.End(err, time.Since(started))
+	return err
+}
+
+func (t Task) reporter() Reporter {
+	if t.Reporter != nil {
+		return t.Reporter
 	}
 
-	_, _ = fmt.Fprintln(t.Output, styleDone(" Done"))
-	return nil
+	return NewReporter(t.Output, t.Error)
 }